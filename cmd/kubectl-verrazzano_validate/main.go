@@ -0,0 +1,120 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+// kubectl-verrazzano_validate is a kubectl plugin, run as kubectl verrazzano-validate, that submits models and
+// bindings to the cluster with dryRun=All and prints the problems found by the Verrazzano admission controller
+// grouped by component.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/verrazzano/verrazzano-admission-controllers/pkg"
+	v8oclientset "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/typed/verrazzano/v1beta1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// manifestPaths is a flag that can be given several times
+type manifestPaths []string
+
+func (m *manifestPaths) String() string {
+	return strings.Join(*m, ",")
+}
+
+func (m *manifestPaths) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// Submit the models and bindings of the files given with -f.  Returns the exit code, 1 if a model or binding
+// is not valid and 2 if the validation could not be run.
+func run(args []string) int {
+	flags := flag.NewFlagSet("kubectl verrazzano-validate", flag.ContinueOnError)
+	var paths manifestPaths
+	flags.Var(&paths, "f", "File or directory containing the models and bindings to validate.  Can be given several times.")
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file, the default kubectl configuration is used if not set.")
+	kubeContext := flags.String("context", "", "Name of the kubeconfig context to use, the current context is used if not set.")
+	namespace := flags.String("namespace", "", "Namespace of the models and bindings without a namespace, the namespace of the kubeconfig context is used if not set.")
+	flags.StringVar(namespace, "n", "", "Shorthand for -namespace.")
+	noColor := flags.Bool("no-color", false, "Don't use colors in the output.")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: kubectl verrazzano-validate -f <file or directory> [options]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if len(paths) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: *kubeContext, Context: clientcmdapi.Context{Namespace: *namespace}}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build kubeconfig: %v\n", err)
+		return 2
+	}
+	// Like kubectl, resources without a namespace are submitted in the namespace of the context
+	defaultNamespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get the namespace of the kubeconfig context: %v\n", err)
+		return 2
+	}
+
+	var manifests []pkg.Manifest
+	for _, path := range paths {
+		pathManifests, err := pkg.ReadManifestsInNamespace(path, defaultNamespace)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		manifests = append(manifests, pathManifests...)
+	}
+	v8oClient, err := v8oclientset.NewForConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build Verrazzano clientset: %v\n", err)
+		return 2
+	}
+
+	results := pkg.DryRunManifests(context.Background(), v8oClient, manifests)
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "no VerrazzanoModel or VerrazzanoBinding found")
+		return 2
+	}
+	if err := pkg.WriteDryRunReport(os.Stdout, results, !*noColor && isTerminal(os.Stdout)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	exitCode := 0
+	for _, result := range results {
+		if result.Err != nil {
+			return 2
+		}
+		if len(result.Problems) > 0 {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
+
+// Check if a file is a terminal, colors are only used on terminals unless NO_COLOR is set
+func isTerminal(file *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
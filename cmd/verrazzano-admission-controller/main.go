@@ -11,65 +11,320 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/verrazzano/verrazzano-admission-controllers/pkg"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 	kzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
 const (
 	port = "8080"
+
+	// Validity and renewal of self-managed certificates
+	caValidity                   = 10 * 365 * 24 * time.Hour
+	servingCertificateValidity   = 365 * 24 * time.Hour
+	certificateRenewBefore       = 30 * 24 * time.Hour
+	certificateReconcileInterval = time.Hour
+
+	// Interval at which the ValidatingWebhookConfiguration is reconciled
+	webhookReconcileInterval = 5 * time.Minute
+
+	// Timeouts of the HTTP servers.  Responses are written before the longest webhook timeout of 30 seconds.
+	serverReadTimeout  = 10 * time.Second
+	serverWriteTimeout = 35 * time.Second
+	serverIdleTimeout  = 90 * time.Second
 )
 
 var (
-	tlscert       string
-	tlskey        string
-	verrazzanoURI string
-	zapOptions    = kzap.Options{}
+	tlscert                  string
+	tlskey                   string
+	caFile                   string
+	tlsReloadInterval        time.Duration
+	selfManagedCertificates  bool
+	certificateSecret        string
+	webhookService           string
+	webhookConfiguration     string
+	registerWebhook          bool
+	webhookName              string
+	webhookNamespaceSelector string
+	webhookFailurePolicies   string
+	webhookTimeouts          string
+	webhookOverrides         map[string]pkg.WebhookOverride
+	validationTimeout        time.Duration
+	failOpenOnTimeout        bool
+	verrazzanoURI            string
+	verrazzanoConfigMap      string
+	policyFile               string
+	metricsAddress           string
+	healthAddress            string
+	previewAddress           string
+	zapOptions               = kzap.Options{}
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "preview" {
+		os.Exit(runPreview(os.Args[2:]))
+	}
+
 	flag.StringVar(&tlscert, "tlsCertFile", "/etc/certs/cert.pem", "File containing the x509 Certificate for HTTPS.")
 	flag.StringVar(&tlskey, "tlsKeyFile", "/etc/certs/key.pem", "File containing the x509 private key to --tlsCertFile.")
-	flag.StringVar(&verrazzanoURI, "verrazzanoUri", "", "Verrazzano URI, for example my-verrazzano-1.verrazzano.example.com")
+	flag.StringVar(&caFile, "caFile", "", "File containing the PEM encoded CA bundle of --tlsCertFile, injected in --webhookConfiguration and reloaded when it changes.  The CA bundle is left to other tools if empty, not used with --selfManagedCertificates.")
+	flag.DurationVar(&tlsReloadInterval, "tlsReloadInterval", 10*time.Second, "Interval at which --tlsCertFile, --tlsKeyFile and --caFile are checked for changes, the certificate and CA bundle are reloaded when they change.")
+	flag.BoolVar(&selfManagedCertificates, "selfManagedCertificates", false, "Generate the CA and serving certificate, store them in --certificateSecret, inject the CA in --webhookConfiguration and renew them before they expire, instead of reading --tlsCertFile and --tlsKeyFile.")
+	flag.StringVar(&certificateSecret, "certificateSecret", "verrazzano-system/verrazzano-validation", "Namespace and name of the secret the self-managed certificates are stored in.")
+	flag.StringVar(&webhookService, "webhookService", "verrazzano-system/verrazzano-validation", "Namespace and name of the service of the webhook, used in the webhook configuration and for the DNS names of the self-managed serving certificate.")
+	flag.StringVar(&webhookConfiguration, "webhookConfiguration", "verrazzano-validation", "Name of the ValidatingWebhookConfiguration of the webhook.")
+	flag.BoolVar(&registerWebhook, "registerWebhook", true, "Create --webhookConfiguration from the validators of the webhook and keep it in sync.")
+	flag.StringVar(&webhookName, "webhookName", "verrazzano-validation.oracle.com", "Suffix of the names of the webhooks in --webhookConfiguration, the webhook of a kind is named <kind>.<webhookName>.")
+	flag.StringVar(&webhookNamespaceSelector, "webhookNamespaceSelector", "", "Label selector of the namespaces whose resources are validated, for example verrazzano.io/validation!=disabled.  All namespaces if empty.")
+	flag.StringVar(&webhookFailurePolicies, "webhookFailurePolicies", "", "Comma separated kind=Fail|Ignore pairs overriding the failure policies of the webhooks of the kinds, for example secret=Fail.")
+	flag.StringVar(&webhookTimeouts, "webhookTimeouts", "", "Comma separated kind=seconds pairs overriding the timeouts of the webhooks of the kinds, for example verrazzanomodel=20.")
+	flag.DurationVar(&validationTimeout, "validationTimeout", 0, "Maximum time taken by the validation of a request, which also gets at most 80% of the timeout of the webhook of its kind.  Requests whose validation times out are denied.")
+	flag.BoolVar(&failOpenOnTimeout, "failOpenOnTimeout", false, "Allow the requests whose validation times out.  They are only allowed for kinds whose webhook has the Ignore failure policy otherwise.")
+	flag.StringVar(&verrazzanoURI, "verrazzanoUri", "", "Verrazzano URI, for example my-verrazzano-1.verrazzano.example.com.  Used when the URI is not in the Verrazzano configuration ConfigMap.  Bindings are denied while the URI is not known.")
+	flag.StringVar(&verrazzanoConfigMap, "verrazzanoConfigMap", "", "Namespace and name of a ConfigMap whose verrazzanoUri key overrides the Verrazzano URI, not watched if empty.")
+	flag.StringVar(&policyFile, "policyFile", "", "YAML file containing the validation policy, the default policy is used if not set.")
+	flag.StringVar(&metricsAddress, "metricsAddress", ":9090", "Address the Prometheus metrics are served on over HTTP at /metrics, the metrics are not served if empty.")
+	flag.StringVar(&healthAddress, "healthAddress", ":8081", "Address the liveness and readiness are served on over HTTP at /healthz and /readyz, they are not served if empty.")
+	flag.StringVar(&previewAddress, "previewAddress", "", "Address the preview of the names generated for a binding is served on over HTTP at /preview, it is not served if empty.")
 	zapOptions.BindFlags(flag.CommandLine)
 	flag.Parse()
 	InitLogs(zapOptions)
 
 	zap.S().Infof("Starting Verrazzano validation admission controller")
 
-	certs, err := tls.LoadX509KeyPair(tlscert, tlskey)
+	// Running with the default policy would drop the limits and rule modes of the policy file
+	policy, err := pkg.LoadPolicy(policyFile)
 	if err != nil {
-		zap.S().Errorf("Failed to load key pair: %v", err)
+		zap.S().Fatalf("Failed to load validation policy: %v", err)
 	}
 
+	cfg, err := clientcmd.BuildConfigFromFlags("", "")
+	if err != nil {
+		zap.S().Fatalf("Failed to build kubeconfig: %v", err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		zap.S().Fatalf("Failed to build kubernetes clientset: %v", err)
+	}
+
+	webhookOverrides, err = pkg.ParseWebhookOverrides(webhookFailurePolicies, webhookTimeouts)
+	if err != nil {
+		zap.S().Fatalf("Webhook overrides are not valid: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	var certificates *pkg.CertificateReloader
+	var caBundle func() []byte
+	var caBundleFile *pkg.CABundleFile
+	if selfManagedCertificates {
+		certificates, caBundle = startSelfManagedCertificates(k8sClient, stopCh)
+	} else {
+		certificates, err = pkg.NewCertificateReloader(tlscert, tlskey)
+		if err != nil {
+			zap.S().Fatalf("Failed to load key pair: %v", err)
+		}
+		certificates.Watch(tlsReloadInterval, stopCh)
+		if caFile != "" {
+			if caBundleFile, err = pkg.NewCABundleFile(caFile); err != nil {
+				zap.S().Fatalf("Failed to load CA bundle: %v", err)
+			}
+			caBundle = caBundleFile.CABundle
+		}
+	}
+	var registrar *pkg.WebhookRegistrar
+	if registerWebhook {
+		registrar = startWebhookRegistrar(k8sClient, caBundle, caBundleFile != nil, stopCh)
+	}
+	if caBundleFile != nil {
+		// A rotated CA is injected right away instead of at the next reconciliation of the webhook configuration
+		var onChange func()
+		if registrar != nil {
+			onChange = func() {
+				if err := registrar.Reconcile(); err != nil {
+					zap.S().Errorf("Failed to inject CA bundle in ValidatingWebhookConfiguration %s: %v", webhookConfiguration, err)
+				}
+			}
+		}
+		caBundleFile.Watch(tlsReloadInterval, onChange, stopCh)
+	}
+
+	verrazzanoConfig := pkg.NewVerrazzanoConfig(verrazzanoURI)
+	if verrazzanoConfigMap != "" {
+		watchVerrazzanoConfig(verrazzanoConfig, k8sClient, stopCh)
+	}
+
+	health := &pkg.HealthChecker{}
+	health.AddLivenessCheck("certificate", pkg.CertificateCheck(certificates.Certificate))
+	health.AddReadinessCheck("verrazzano-config", verrazzanoConfig.Synced)
+	health.AddReadinessCheck("api-server", pkg.APIServerCheck(k8sClient))
+
 	// define http server and server handler
 	server := &http.Server{
-		Addr:      fmt.Sprintf(":%v", port),
-		TLSConfig: &tls.Config{Certificates: []tls.Certificate{certs}},
+		Addr:         fmt.Sprintf(":%v", port),
+		TLSConfig:    &tls.Config{GetCertificate: certificates.GetCertificate},
+		ReadTimeout:  serverReadTimeout,
+		WriteTimeout: serverWriteTimeout,
+		IdleTimeout:  serverIdleTimeout,
 	}
 	sh := pkg.ServerHandler{
-		VerrazzanoURI: verrazzanoURI,
+		VerrazzanoConfig:  verrazzanoConfig,
+		Policy:            policy,
+		WebhookOverrides:  webhookOverrides,
+		ValidationTimeout: validationTimeout,
+		FailOpenOnTimeout: failOpenOnTimeout,
 	}
 	mux := http.NewServeMux()
-	mux.HandleFunc("/validate", sh.Serve)
+	sh.HandleValidators(mux)
 	server.Handler = mux
 
 	// start webhook server
 	go func() {
-		if err := server.ListenAndServeTLS("", ""); err != nil {
-			zap.S().Errorf("Failed to listen and serve webhook server: %v", err)
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			zap.S().Fatalf("Failed to listen and serve webhook server: %v", err)
 		}
 	}()
 
 	zap.S().Infof("Server running listening in port: %s", port)
 
+	// start metrics, health and preview servers
+	var servers []*http.Server
+	if metricsAddress != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", pkg.MetricsHandler())
+		servers = append(servers, startHTTPServer("metrics", metricsAddress, metricsMux))
+	}
+	if healthAddress != "" {
+		healthMux := http.NewServeMux()
+		healthMux.HandleFunc("/healthz", health.ServeHealthz)
+		healthMux.HandleFunc("/readyz", health.ServeReadyz)
+		servers = append(servers, startHTTPServer("health", healthAddress, healthMux))
+	}
+	if previewAddress != "" {
+		previewMux := http.NewServeMux()
+		previewMux.HandleFunc("/preview", sh.ServePreview)
+		servers = append(servers, startHTTPServer("preview", previewAddress, previewMux))
+	}
+
 	// listen for shutdown signal
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
 	<-signalChan
 
 	zap.S().Infow("Got shutdown signal, shutting down webhook server gracefully...")
+	close(stopCh)
 	server.Shutdown(context.Background())
+	for _, httpServer := range servers {
+		httpServer.Shutdown(context.Background())
+	}
+}
+
+// Start a plain HTTP server, exiting if it can't listen on its address
+func startHTTPServer(name string, address string, handler http.Handler) *http.Server {
+	httpServer := &http.Server{
+		Addr:         address,
+		Handler:      handler,
+		ReadTimeout:  serverReadTimeout,
+		WriteTimeout: serverWriteTimeout,
+		IdleTimeout:  serverIdleTimeout,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			zap.S().Fatalf("Failed to listen and serve %s server: %v", name, err)
+		}
+	}()
+	zap.S().Infof("Serving %s at %s", name, address)
+	return httpServer
+}
+
+// Watch the Verrazzano configuration ConfigMap given as <namespace>/<name> for the Verrazzano URI
+func watchVerrazzanoConfig(verrazzanoConfig *pkg.VerrazzanoConfig, k8sClient kubernetes.Interface, stopCh <-chan struct{}) {
+	namespace, name, err := parseNamespacedName(verrazzanoConfigMap)
+	if err != nil {
+		zap.S().Errorf("Verrazzano configuration ConfigMap %s is not valid: %v", verrazzanoConfigMap, err)
+		return
+	}
+	verrazzanoConfig.Watch(k8sClient, namespace, name, stopCh)
+}
+
+// Generate the webhook certificates and keep them renewed, exiting if no certificate can be served.  Returns the
+// reloader serving the certificate and a function returning the CA bundle.
+func startSelfManagedCertificates(k8sClient kubernetes.Interface, stopCh <-chan struct{}) (*pkg.CertificateReloader, func() []byte) {
+	secretNamespace, secretName, err := parseNamespacedName(certificateSecret)
+	if err != nil {
+		zap.S().Fatalf("Certificate secret %s is not valid: %v", certificateSecret, err)
+	}
+	serviceNamespace, serviceName, err := parseNamespacedName(webhookService)
+	if err != nil {
+		zap.S().Fatalf("Webhook service %s is not valid: %v", webhookService, err)
+	}
+
+	reloader := &pkg.CertificateReloader{}
+	certificates := &pkg.SelfManagedCertificates{
+		K8sClient:                k8sClient,
+		SecretNamespace:          secretNamespace,
+		SecretName:               secretName,
+		WebhookConfigurationName: webhookConfiguration,
+		DNSNames: []string{
+			fmt.Sprintf("%s.%s.svc", serviceName, serviceNamespace),
+			serviceName,
+			fmt.Sprintf("%s.%s", serviceName, serviceNamespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, serviceNamespace),
+		},
+		CAValidity:      caValidity,
+		ServingValidity: servingCertificateValidity,
+		RenewBefore:     certificateRenewBefore,
+		Reloader:        reloader,
+	}
+	if err := certificates.Reconcile(); err != nil {
+		if certificate, _ := reloader.Certificate(); certificate == nil {
+			zap.S().Fatalf("Failed to generate webhook certificates: %v", err)
+		}
+		zap.S().Errorf("Failed to reconcile webhook certificates: %v", err)
+	}
+	certificates.Run(certificateReconcileInterval, stopCh)
+	return reloader, certificates.CABundle
+}
+
+// Create the ValidatingWebhookConfiguration of the webhook and keep it in sync with the validators.  The CA bundle
+// replaces the one of the existing webhooks when replaceCABundle is set.
+func startWebhookRegistrar(k8sClient kubernetes.Interface, caBundle func() []byte, replaceCABundle bool, stopCh <-chan struct{}) *pkg.WebhookRegistrar {
+	serviceNamespace, serviceName, err := parseNamespacedName(webhookService)
+	if err != nil {
+		zap.S().Fatalf("Webhook service %s is not valid: %v", webhookService, err)
+	}
+	var namespaceSelector *metav1.LabelSelector
+	if webhookNamespaceSelector != "" {
+		if namespaceSelector, err = metav1.ParseToLabelSelector(webhookNamespaceSelector); err != nil {
+			zap.S().Fatalf("Webhook namespace selector %s is not valid: %v", webhookNamespaceSelector, err)
+		}
+	}
+	registrar := &pkg.WebhookRegistrar{
+		K8sClient:         k8sClient,
+		Name:              webhookConfiguration,
+		WebhookName:       webhookName,
+		ServiceNamespace:  serviceNamespace,
+		ServiceName:       serviceName,
+		NamespaceSelector: namespaceSelector,
+		CABundle:          caBundle,
+		ReplaceCABundle:   replaceCABundle,
+		Overrides:         webhookOverrides,
+	}
+	registrar.Run(webhookReconcileInterval, stopCh)
+	return registrar
+}
+
+// Parse a <namespace>/<name> value
+func parseNamespacedName(value string) (string, string, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("expected <namespace>/<name>")
+	}
+	return parts[0], parts[1], nil
 }
@@ -0,0 +1,56 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/verrazzano/verrazzano-admission-controllers/pkg"
+)
+
+// Print the names of the resources created for a binding and its model.  Returns the exit code, 1 if the
+// generated names are not valid and 2 if the preview could not be created.
+func runPreview(args []string) int {
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	bindingFile := flags.String("binding", "", "YAML file containing the VerrazzanoBinding.")
+	modelFile := flags.String("model", "", "YAML file containing the VerrazzanoModel of the binding.")
+	uri := flags.String("verrazzanoUri", "", "Verrazzano URI, for example my-verrazzano-1.verrazzano.example.com")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *bindingFile == "" || *modelFile == "" {
+		fmt.Fprintln(os.Stderr, "usage: verrazzano-admission-controller preview -binding <file> -model <file> [-verrazzanoUri <uri>]")
+		return 2
+	}
+
+	binding, err := pkg.ReadBindingFile(*bindingFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	model, err := pkg.ReadModelFile(*modelFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if binding.Spec.ModelName != model.Name {
+		fmt.Fprintf(os.Stderr, "binding %s references model %s, not %s\n", binding.Name, binding.Spec.ModelName, model.Name)
+		return 2
+	}
+
+	preview := pkg.PreviewBinding(*model, *binding, *uri)
+	out, err := json.MarshalIndent(preview, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fmt.Println(string(out))
+	if len(preview.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+// verrazzano-validate runs the validations of the Verrazzano admission controller on model and binding manifest
+// files without a cluster.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/verrazzano/verrazzano-admission-controllers/pkg"
+)
+
+// manifestPaths is a flag that can be given several times
+type manifestPaths []string
+
+func (m *manifestPaths) String() string {
+	return strings.Join(*m, ",")
+}
+
+func (m *manifestPaths) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// Validate the models and bindings in the files given as arguments.  Returns the exit code, 1 if a model or
+// binding is not valid and 2 if the validation could not be run.
+func run(args []string) int {
+	flags := flag.NewFlagSet("verrazzano-validate", flag.ContinueOnError)
+	var lookupPaths manifestPaths
+	flags.Var(&lookupPaths, "manifests", "File or directory of manifests, like secrets, managed clusters and other models, used to resolve lookups.  Can be given several times.")
+	verrazzanoURI := flags.String("verrazzanoUri", "", "Verrazzano URI, for example my-verrazzano-1.verrazzano.example.com")
+	policyFile := flags.String("policyFile", "", "YAML file containing the validation policy, the default policy is used if not set.")
+	format := flags.String("format", pkg.ReportFormatText, "Format of the report: text, json, junit or sarif.")
+	output := flags.String("output", "", "File the report is written to, standard output is used if not set.")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: verrazzano-validate [options] <file or directory>...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	policy, err := pkg.LoadPolicy(*policyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	targets, err := readManifests(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	lookups, err := readManifests(lookupPaths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := pkg.ValidateManifests(targets, lookups, *verrazzanoURI, policy)
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "no VerrazzanoModel or VerrazzanoBinding found")
+		return 2
+	}
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := pkg.WriteReport(out, *format, results); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	for _, result := range results {
+		if result.Message != "" {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Read the manifests of all the paths
+func readManifests(paths []string) ([]pkg.Manifest, error) {
+	var manifests []pkg.Manifest
+	for _, path := range paths {
+		pathManifests, err := pkg.ReadManifests(path)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, pathManifests...)
+	}
+	return manifests, nil
+}
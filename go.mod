@@ -8,10 +8,13 @@ go 1.13
 require (
 	github.com/onsi/ginkgo v1.12.0
 	github.com/onsi/gomega v1.9.0
+	github.com/prometheus/client_golang v1.5.1
+	github.com/prometheus/client_model v0.2.0
 	github.com/stretchr/testify v1.5.1
 	github.com/verrazzano/verrazzano-crd-generator v0.0.0-20201214161122-0330d094db41
 	go.uber.org/zap v1.16.0
 	gopkg.in/yaml.v2 v2.2.8
+	gopkg.in/yaml.v3 v3.0.1
 	k8s.io/api v0.18.2
 	k8s.io/apiextensions-apiserver v0.18.2
 	k8s.io/apimachinery v0.18.2
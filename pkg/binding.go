@@ -5,167 +5,303 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	s "strings"
+	"time"
 
 	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
 	"go.uber.org/zap"
 	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sValidations "k8s.io/apimachinery/pkg/util/validation"
 )
 
+// Validates the creation and update of bindings
+type bindingValidator struct{}
+
+func (bindingValidator) Registration() ValidatorRegistration {
+	return ValidatorRegistration{Kind: "VerrazzanoBinding", Group: "verrazzano.io", Version: "v1beta1", Resource: "verrazzanobindings",
+		Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update}}
+}
+
+func (bindingValidator) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	binding := v1beta1v8o.VerrazzanoBinding{}
+	if err := json.Unmarshal(request.Object.Raw, &binding); err != nil {
+		zap.S().Errorf("error with unmarshal of VerrazzanoBinding: %v", err)
+		return DenyProblems(withRule(ruleInvalidObject, []Problem{newProblem("error with unmarshal of VerrazzanoBinding: %v", err)})...)
+	}
+	zap.S().Infof("processing binding name: %s:%s", binding.Namespace, binding.Name)
+	arRequest := v1beta1.AdmissionReview{Request: request.AdmissionRequest}
+	return problemsResult(validateBinding(ctx, arRequest, binding, request.Clientsets, request.VerrazzanoURI, request.Policy))
+}
+
 // Validate binding
-func validateBinding(arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets, verrazzanoURI string) v1beta1.AdmissionReview {
+func validateBinding(ctx context.Context, arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets, verrazzanoURI string, policy *Policy) []Problem {
 	// Don't allow create if the binding refers to a non-existing model
-	modelList, err := clientsets.V8oClient.VerrazzanoModels(arRequest.Request.Namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil && modelList != nil {
-		modelFound := false
-		for _, model := range modelList.Items {
-			if binding.Spec.ModelName == model.Name {
-				modelFound = true
-				break
-			}
-		}
-		if !modelFound {
-			message := fmt.Sprintf("binding is referencing model %s that does not exist in namespace %s", binding.Spec.ModelName, arRequest.Request.Namespace)
-			zap.S().Errorw(message)
-			return errorAdmissionReview(message)
-		}
+	model, err := getModel(ctx, clientsets, arRequest.Request.Namespace, binding.Spec.ModelName)
+	if k8sErrors.IsNotFound(err) {
+		problem := newFieldProblem("spec.modelName", problemTypeNotFound, "binding is referencing model %s that does not exist in namespace %s", binding.Spec.ModelName, arRequest.Request.Namespace)
+		zap.S().Errorw(problem.Message)
+		return withRule(ruleBindingModel, []Problem{problem})
+	} else if err != nil {
+		problem := newFieldProblem("spec.modelName", "", "failed to get referenced model %s in namespace %s: %v", binding.Spec.ModelName, arRequest.Request.Namespace, err)
+		zap.S().Errorw(problem.Message)
+		return withRule(ruleModelLookup, []Problem{problem})
 	}
 
+	// Every check runs so that the problems of the rules that are not enforced don't hide the others.
 	// All names that reference a k8s name must be valid.
-	response := validateBindingResourceNames(binding)
-	if response != "" {
-		return errorAdmissionReview(response)
-	}
+	problems := withRule(ruleResourceNames, validateBindingResourceNames(binding))
 
-	// Verify that the length of the VMI domain name is not greater than 64
-	const VmiDomainNameFormat = "*.vmi.%s.%s"
-	const MaxVmiDomainNameLen = 64
-	domainName := fmt.Sprintf(VmiDomainNameFormat, binding.Name, verrazzanoURI)
-	domainNameLen := len(domainName)
-	if domainNameLen > MaxVmiDomainNameLen {
-		message := fmt.Sprintf("the VMI domain name is greater than %d characters: %s.  The binding name %s is %d characters long.  Reduce the size by using a binding name that is at least %d characters shorter.", MaxVmiDomainNameLen, domainName, binding.Name, len(binding.Name), domainNameLen-MaxVmiDomainNameLen)
-		zap.S().Errorw(message)
-		return errorAdmissionReview(message)
-	}
+	// Verify that the hostnames created for the binding, like the VMI domain name, are not too long
+	problems = append(problems, withRule(ruleGeneratedHostnames, validateGeneratedHostnames(binding, verrazzanoURI))...)
 
 	// All placements names in the binding must have a matching VerrazzanoManagedClusters custom resource
-	response = validateClusters(arRequest, binding, clientsets)
-	if response != "" {
-		return errorAdmissionReview(response)
+	problems = append(problems, withRule(ruleClusterReferences, validateClusters(ctx, arRequest, binding, clientsets))...)
+
+	problems = append(problems, withRule(rulePlacementNamespaces, validatePlacementNamespaces(binding))...)
+
+	// Validate Ingress Bindings
+	problems = append(problems, withRule(ruleIngressBindings, validateIngressBinding(binding.Spec.IngressBindings))...)
+
+	// Validate components in the binding
+	problems = append(problems, withRule(ruleBindingComponents, validateComponents(binding, *model))...)
+
+	// Replicas of component bindings must be allowed by the policy
+	bindingPolicy := policy.ForNamespace(arRequest.Request.Namespace)
+	problems = append(problems, withRule(ruleBindingReplicas, validateBindingReplicas(binding, bindingPolicy))...)
+	problems = append(problems, withRule(ruleManagedClusterReplicas,
+		validateManagedClusterReplicas(ctx, arRequest, binding, clientsets, bindingPolicy))...)
+
+	// Database binding URLs must be valid JDBC URLs referencing allowed hosts
+	problems = append(problems, withRule(ruleDatabaseBindings, validateDatabaseBindings(binding, bindingPolicy))...)
+
+	// Helidon applications placed into the same namespace of a cluster must not use the same ports
+	problems = append(problems, withRule(ruleHelidonPlacementPorts, validateBindingHelidonPlacements(ctx, arRequest, binding, *model, clientsets))...)
+
+	// All secrets in the binding must be defined in the default namespace.
+	problems = append(problems, withRule(ruleSecretReferences, validateBindingSecrets(ctx, binding, clientsets))...)
+	if len(problems) > 0 {
+		return problems
 	}
 
-	response = validatePlacementNamespaces(binding)
-	if response != "" {
-		return errorAdmissionReview(response)
+	zap.S().Infow("validation of binding successful")
+	return nil
+}
+
+// componentReplicas is the number of replicas of a component binding
+type componentReplicas struct {
+	field    string
+	name     string
+	replicas int32
+}
+
+// Get the number of replicas of each component binding.  Bindings that don't specify replicas use the defaults of
+// the operators of the components and are skipped.
+func getComponentReplicas(binding v1beta1v8o.VerrazzanoBinding) []componentReplicas {
+	var result []componentReplicas
+	add := func(field string, name string, replicas *int32) {
+		if replicas != nil {
+			result = append(result, componentReplicas{field: field + ".replicas", name: name, replicas: *replicas})
+		}
+	}
+	for i, weblogicBinding := range binding.Spec.WeblogicBindings {
+		add(fmt.Sprintf("spec.weblogicBindings[%d]", i), weblogicBinding.Name, weblogicBinding.Replicas)
+	}
+	for i, coherenceBinding := range binding.Spec.CoherenceBindings {
+		add(fmt.Sprintf("spec.coherenceBindings[%d]", i), coherenceBinding.Name, coherenceBinding.Replicas)
+	}
+	for i, helidonBinding := range binding.Spec.HelidonBindings {
+		add(fmt.Sprintf("spec.helidonBindings[%d]", i), helidonBinding.Name, helidonBinding.Replicas)
+	}
+	return result
+}
+
+// Validate that the replicas of the component bindings are not negative and are within the limits of the policy
+func validateBindingReplicas(binding v1beta1v8o.VerrazzanoBinding, policy Policy) []Problem {
+	zap.S().Debugw("In validateBindingReplicas code")
+
+	var errMessages []Problem
+	for _, component := range getComponentReplicas(binding) {
+		if component.replicas < 0 {
+			errMessages = append(errMessages, fieldProblem(component.field, problemTypeInvalid,
+				fmt.Sprintf("%d: must be greater than or equal to 0", component.replicas)))
+		} else if policy.MinBindingReplicas > 0 && int(component.replicas) < policy.MinBindingReplicas {
+			errMessages = append(errMessages, fieldProblem(component.field, problemTypeInvalid,
+				fmt.Sprintf("%d: must be greater than or equal to %d", component.replicas, policy.MinBindingReplicas)))
+		} else if policy.MaxBindingReplicas > 0 && int(component.replicas) > policy.MaxBindingReplicas {
+			errMessages = append(errMessages, fieldProblem(component.field, problemTypeInvalid,
+				fmt.Sprintf("%d: must be less than or equal to %d", component.replicas, policy.MaxBindingReplicas)))
+		}
 	}
 
-	// Validate Ingress Bindings
-	errMessages := validateIngressBinding(binding.Spec.IngressBindings)
 	if len(errMessages) > 0 {
-		return errorAdmissionReview(s.Join(errMessages, ", "))
+		zap.S().Errorw(problemsMessage(errMessages))
+	}
+	return errMessages
+}
+
+// Validate that the total number of replicas placed on each VerrazzanoManagedCluster by the bindings of all
+// namespaces, including this binding, doesn't exceed the limit of the policy.  A placement references the
+// VerrazzanoManagedCluster of the namespace of its binding.  Only components with a WebLogic, Coherence or Helidon
+// binding that specifies replicas are counted.
+func validateManagedClusterReplicas(ctx context.Context, arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets, policy Policy) []Problem {
+	zap.S().Debugw("In validateManagedClusterReplicas code")
+
+	if policy.MaxManagedClusterReplicas <= 0 {
+		return nil
+	}
+	bindingList, err := listBindings(ctx, clientsets, "")
+	if err != nil {
+		problem := newProblem("failed to list bindings in all namespaces: %v", err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+
+	totals := make(map[string]int)
+	for _, other := range bindingList.Items {
+		// The binding being updated is replaced by the new version
+		if other.Namespace != arRequest.Request.Namespace || other.Name != binding.Name {
+			addManagedClusterReplicas(other, other.Namespace, totals)
+		}
+	}
+	addManagedClusterReplicas(binding, arRequest.Request.Namespace, totals)
+
+	var errMessages []Problem
+	reported := make(map[string]bool)
+	for i, placement := range binding.Spec.Placement {
+		key := arRequest.Request.Namespace + "/" + placement.Name
+		if reported[key] || totals[key] <= policy.MaxManagedClusterReplicas {
+			continue
+		}
+		reported[key] = true
+		errMessages = append(errMessages, fieldProblem(fmt.Sprintf("spec.placement[%d].name", i), problemTypeForbidden,
+			fmt.Sprintf("bindings place %d replicas on cluster %s in namespace %s, the maximum allowed is %d", totals[key], placement.Name, arRequest.Request.Namespace, policy.MaxManagedClusterReplicas)))
 	}
 
-	// Validate components in the binding
-	errMessages = validateComponents(arRequest, binding, clientsets)
 	if len(errMessages) > 0 {
-		return errorAdmissionReview(s.Join(errMessages, ", "))
+		zap.S().Errorw(problemsMessage(errMessages))
 	}
+	return errMessages
+}
 
-	// All secrets in the binding must be defined in the default namespace.
-	response = validateBindingSecrets(binding, clientsets)
-	if response != "" {
-		return errorAdmissionReview(response)
+// Validate the ports of the Helidon applications placed by a binding against the other Helidon applications placed
+// into the same namespace of a cluster.  The binding being updated is replaced by the new version.
+func validateBindingHelidonPlacements(ctx context.Context, arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
+	zap.S().Debugw("In validateBindingHelidonPlacements code")
+
+	if len(model.Spec.HelidonApplications) == 0 {
+		return nil
+	}
+	bindingList, err := listBindings(ctx, clientsets, "")
+	if err != nil {
+		problem := newProblem("failed to list bindings in all namespaces: %v", err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
 	}
 
-	zap.S().Infow("validation of binding successful")
-	return v1beta1.AdmissionReview{}
+	binding.Namespace = arRequest.Request.Namespace
+	return validateHelidonBindingPlacements(ctx, model, []v1beta1v8o.VerrazzanoBinding{binding}, bindingList.Items, clientsets)
+}
+
+// Add the replicas of the components placed by a binding of a namespace to the totals, keyed by the namespace and
+// name of the cluster
+func addManagedClusterReplicas(binding v1beta1v8o.VerrazzanoBinding, namespace string, totals map[string]int) {
+	replicas := make(map[string]int)
+	for _, component := range getComponentReplicas(binding) {
+		if component.replicas > 0 {
+			replicas[component.name] = int(component.replicas)
+		}
+	}
+	for _, placement := range binding.Spec.Placement {
+		for _, placementNamespace := range placement.Namespaces {
+			for _, component := range placementNamespace.Components {
+				totals[namespace+"/"+placement.Name] += replicas[component.Name]
+			}
+		}
+	}
 }
 
 // Validate names that will be used as Kubernetes resource names.
 // A validate k8s resource name must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an
 // alphanumeric character.  We use k8s validation functions to check the validity of names.
-func validateBindingResourceNames(binding v1beta1v8o.VerrazzanoBinding) string {
+func validateBindingResourceNames(binding v1beta1v8o.VerrazzanoBinding) []Problem {
 	zap.S().Debugw("In validateBindingResourceNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	// Check if namespace names are valid
 	for i, placement := range binding.Spec.Placement {
 		for j, namespace := range placement.Namespaces {
 			field := fmt.Sprintf("spec.placement[%d].namespaces[%d].name", i, j)
-			errMessages = addInvalidNameFormatMessage(namespace.Name, field, errMessages)
+			errMessages = addInvalidNameProblems(namespace.Name, field, errMessages)
 		}
 	}
 
 	// Check if database credentials names are valid
 	for i, dbBinding := range binding.Spec.DatabaseBindings {
 		field := fmt.Sprintf("spec.databaseBindings[%d].credential", i)
-		errMessages = addInvalidNameFormatMessage(dbBinding.Credentials, field, errMessages)
-	}
-
-	if len(errMessages) > 0 {
-		return s.Join(errMessages, "")
+		errMessages = addInvalidNameProblems(dbBinding.Credentials, field, errMessages)
 	}
 
-	return ""
+	return errMessages
 }
 
 // Validate that the default namespace is not used in a binding placement
-func validatePlacementNamespaces(binding v1beta1v8o.VerrazzanoBinding) string {
+func validatePlacementNamespaces(binding v1beta1v8o.VerrazzanoBinding) []Problem {
 	zap.S().Debugw("In validatePlacementNamespaces code")
 
-	for _, placement := range binding.Spec.Placement {
-		for _, namespace := range placement.Namespaces {
+	for i, placement := range binding.Spec.Placement {
+		for j, namespace := range placement.Namespaces {
 			if namespace.Name == "default" {
-				message := "default namespace is not allowed in placements of binding"
-				zap.S().Errorw(message)
-				return message
+				problem := newFieldProblem(fmt.Sprintf("spec.placement[%d].namespaces[%d].name", i, j), problemTypeForbidden,
+					"default namespace is not allowed in placements of binding")
+				zap.S().Errorw(problem.Message)
+				return []Problem{problem}
 			}
 		}
 	}
 
-	return ""
+	return nil
 }
 
-// Validate componets in the binding
-func validateComponents(arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets) []string {
+// Validate componets in the binding against the model referenced by the binding
+func validateComponents(binding v1beta1v8o.VerrazzanoBinding, model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateComponents code")
 
-	var errMessages []string
-	// Get all components referenced in the binding
-	componentsInBindingSet := make(map[string]bool)
+	var errMessages []Problem
+	// Get all components referenced in the binding, with the field of their first occurrence
+	componentsInBindingSet := make(map[string]string)
 
 	// All components should only occur once across all binding types being validated within the current binding yaml.
-	for _, coherenceBinding := range binding.Spec.CoherenceBindings {
-		if !componentsInBindingSet[coherenceBinding.Name] {
-			componentsInBindingSet[coherenceBinding.Name] = true
+	for i, coherenceBinding := range binding.Spec.CoherenceBindings {
+		field := fmt.Sprintf("spec.coherenceBindings[%d].name", i)
+		if _, ok := componentsInBindingSet[coherenceBinding.Name]; !ok {
+			componentsInBindingSet[coherenceBinding.Name] = field
 		} else {
-			errMessages = append(errMessages, fmt.Sprintf("Multiple occurrence of component for Coherence binding. Invalid Component: [%s]\n", coherenceBinding.Name))
+			errMessages = append(errMessages, newFieldProblem(field, problemTypeDuplicate, "Multiple occurrence of component for Coherence binding. Invalid Component: [%s]", coherenceBinding.Name))
 		}
 	}
-	for _, helidonBinding := range binding.Spec.HelidonBindings {
-		if !componentsInBindingSet[helidonBinding.Name] {
-			componentsInBindingSet[helidonBinding.Name] = true
+	for i, helidonBinding := range binding.Spec.HelidonBindings {
+		field := fmt.Sprintf("spec.helidonBindings[%d].name", i)
+		if _, ok := componentsInBindingSet[helidonBinding.Name]; !ok {
+			componentsInBindingSet[helidonBinding.Name] = field
 		} else {
-			errMessages = append(errMessages, fmt.Sprintf("Multiple occurrence of component for Helidon binding. Invalid Component: [%s]\n", helidonBinding.Name))
+			errMessages = append(errMessages, newFieldProblem(field, problemTypeDuplicate, "Multiple occurrence of component for Helidon binding. Invalid Component: [%s]", helidonBinding.Name))
 		}
 	}
-	for _, weblogicBinding := range binding.Spec.WeblogicBindings {
-		if !componentsInBindingSet[weblogicBinding.Name] {
-			componentsInBindingSet[weblogicBinding.Name] = true
+	for i, weblogicBinding := range binding.Spec.WeblogicBindings {
+		field := fmt.Sprintf("spec.weblogicBindings[%d].name", i)
+		if _, ok := componentsInBindingSet[weblogicBinding.Name]; !ok {
+			componentsInBindingSet[weblogicBinding.Name] = field
 		} else {
-			errMessages = append(errMessages, fmt.Sprintf("Multiple occurrence of component for Weblogic binding. Invalid Component: [%s]\n", weblogicBinding.Name))
+			errMessages = append(errMessages, newFieldProblem(field, problemTypeDuplicate, "Multiple occurrence of component for Weblogic binding. Invalid Component: [%s]", weblogicBinding.Name))
 		}
 	}
 
-	// Get model referenced in the binding
-	modelName := binding.Spec.ModelName
-	model, _ := clientsets.V8oClient.VerrazzanoModels(arRequest.Request.Namespace).Get(context.TODO(), modelName, metav1.GetOptions{})
-
 	// Get all components referenced in the model
 	componentsInModel := make(map[string]bool)
 	for _, coherenceCluster := range model.Spec.CoherenceClusters {
@@ -182,48 +318,49 @@ func validateComponents(arRequest v1beta1.AdmissionReview, binding v1beta1v8o.Ve
 	}
 
 	// Each componentsInBindingSet component must be present in componentsInModel
-	for bindingComponent := range componentsInBindingSet {
+	for bindingComponent, field := range componentsInBindingSet {
 		if !componentsInModel[bindingComponent] {
-			errMessages = append(errMessages, fmt.Sprintf("Component in bindings does not exist in model definition. Invalid Component: [%s]\n", bindingComponent))
+			errMessages = append(errMessages, newFieldProblem(field, problemTypeNotFound, "Component in bindings does not exist in model definition. Invalid Component: [%s]", bindingComponent))
 		}
 	}
 
 	// Get all components referenced in the placement namespaces
-	componentsInPlacementNamespacesSet := make(map[string]bool)
+	componentsInPlacementNamespacesSet := make(map[string]string)
 
-	for _, placement := range binding.Spec.Placement {
-		for _, namespace := range placement.Namespaces {
-			for _, component := range namespace.Components {
-				if !componentsInPlacementNamespacesSet[component.Name] {
-					componentsInPlacementNamespacesSet[component.Name] = true
+	for i, placement := range binding.Spec.Placement {
+		for j, namespace := range placement.Namespaces {
+			for k, component := range namespace.Components {
+				field := fmt.Sprintf("spec.placement[%d].namespaces[%d].components[%d].name", i, j, k)
+				if _, ok := componentsInPlacementNamespacesSet[component.Name]; !ok {
+					componentsInPlacementNamespacesSet[component.Name] = field
 				} else {
-					errMessages = append(errMessages, fmt.Sprintf("Multiple occurrence of component across placement namespaces. Invalid Component: [%s]\n", component.Name))
+					errMessages = append(errMessages, newFieldProblem(field, problemTypeDuplicate, "Multiple occurrence of component across placement namespaces. Invalid Component: [%s]", component.Name))
 				}
 			}
 		}
 	}
 	// Each componentsInPlacementNamespacesSet component must be present in componentsInModel
-	for component := range componentsInPlacementNamespacesSet {
+	for component, field := range componentsInPlacementNamespacesSet {
 		if !componentsInModel[component] {
-			errMessages = append(errMessages, fmt.Sprintf("Component in placement namespace does not exist in model definition. Invalid Component: [%s]\n", component))
+			errMessages = append(errMessages, newFieldProblem(field, problemTypeNotFound, "Component in placement namespace does not exist in model definition. Invalid Component: [%s]", component))
 		}
 	}
 
 	if len(errMessages) > 0 {
-		zap.S().Errorw(s.Join(errMessages, ", "))
+		zap.S().Errorw(problemsMessage(errMessages))
 	}
 	return errMessages
 }
 
 // Validate ingressBindings
-func validateIngressBinding(ingressBindings []v1beta1v8o.VerrazzanoIngressBinding) []string {
+func validateIngressBinding(ingressBindings []v1beta1v8o.VerrazzanoIngressBinding) []Problem {
 	zap.S().Debugw("In validateIngressBinding code")
 
-	var errMessages []string
-	for _, ingressBinding := range ingressBindings {
+	var errMessages []Problem
+	for i, ingressBinding := range ingressBindings {
 		// validate ingressBinding > dnsName
 		dnsName := s.TrimSpace(ingressBinding.DnsName)
-		errFound := false
+		var dnsMessages []string
 
 		// Special case for Verrazzano binding definition where we consider a single * for dnsName as valid.
 		if dnsName == "*" {
@@ -231,95 +368,106 @@ func validateIngressBinding(ingressBindings []v1beta1v8o.VerrazzanoIngressBindin
 		}
 
 		if s.HasPrefix(dnsName, "*.") {
-			for _, msg := range k8sValidations.IsWildcardDNS1123Subdomain(dnsName) {
-				errMessages = append(errMessages, msg)
-				errFound = true
-			}
+			dnsMessages = k8sValidations.IsWildcardDNS1123Subdomain(dnsName)
 		} else {
-			for _, msg := range k8sValidations.IsDNS1123Subdomain(dnsName) {
-				errMessages = append(errMessages, msg)
-				errFound = true
-			}
+			dnsMessages = k8sValidations.IsDNS1123Subdomain(dnsName)
 		}
 
-		if !errFound {
+		if len(dnsMessages) == 0 {
 			// Validate labels in the DNS name.
-			labels := s.Split(dnsName, ".")
-			for i := range labels {
-				label := labels[i]
-				for _, msg := range k8sValidations.IsDNS1123Label(label) {
-					errMessages = append(errMessages, msg)
-					errFound = true
-				}
+			for _, label := range s.Split(dnsName, ".") {
+				dnsMessages = append(dnsMessages, k8sValidations.IsDNS1123Label(label)...)
 			}
 		}
 
-		if errFound {
-			errMessages = append(errMessages, fmt.Sprintf("Invalid DNS name: [%s]\n", dnsName))
-			zap.S().Errorw(s.Join(errMessages, ", "))
+		if len(dnsMessages) > 0 {
+			problem := fieldProblem(fmt.Sprintf("spec.ingressBindings[%d].dnsName", i), problemTypeInvalid,
+				fmt.Sprintf("\"%s\": %s, Invalid DNS name: [%s]", dnsName, s.Join(dnsMessages, ", "), dnsName))
+			zap.S().Errorw(problem.Message)
+			errMessages = append(errMessages, problem)
 		}
 	}
 	return errMessages
 }
 
-// Validate that each placement name has a matching VerrazzanoManagedClusters custom resource
-func validateClusters(arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets) string {
+// Validate that each placement name has a matching VerrazzanoManagedClusters custom resource.  Each cluster is
+// fetched once, concurrently, and the problems are reported in the order of the placements.
+func validateClusters(ctx context.Context, arRequest v1beta1.AdmissionReview, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets) []Problem {
 	zap.S().Debugw("In validateClusters code")
+	defer observeLookup(lookupValidateClusters, time.Now())
 
-	var missingClusters = ""
+	var names []string
 	for _, placement := range binding.Spec.Placement {
-		_, err := clientsets.V8oClient.VerrazzanoManagedClusters(arRequest.Request.Namespace).Get(context.TODO(), placement.Name, metav1.GetOptions{})
+		names = append(names, placement.Name)
+	}
+	names, indexes := distinctValues(names)
+	errs := make([]error, len(names))
+	forEachConcurrently(len(names), func(i int) {
+		_, errs[i] = clientsets.V8oClient.VerrazzanoManagedClusters(arRequest.Request.Namespace).Get(ctx, names[i], metav1.GetOptions{})
+		recordTimedOutLookup(ctx, errs[i], fmt.Sprintf("cluster %s in namespace %s", names[i], arRequest.Request.Namespace))
+	})
+
+	var missingClusters = ""
+	// Field of the first placement that references a missing cluster
+	var missingField string
+	for i, placement := range binding.Spec.Placement {
+		field := fmt.Sprintf("spec.placement[%d].name", i)
+		err := errs[indexes[placement.Name]]
 		if k8sErrors.IsNotFound(err) {
 			if missingClusters != "" {
 				missingClusters += ","
+			} else {
+				missingField = field
 			}
 			missingClusters += placement.Name
 		} else if err != nil {
-			message := fmt.Sprintf("failed to get referenced cluster %s in namespace %s: %v", placement.Name, arRequest.Request.Namespace, err)
-			zap.S().Errorw(message)
-			return message
+			problem := newFieldProblem(field, "", "failed to get referenced cluster %s in namespace %s: %v", placement.Name, arRequest.Request.Namespace, err)
+			zap.S().Errorw(problem.Message)
+			return []Problem{problem}
 		}
 	}
 
-	var message = ""
 	if missingClusters != "" {
-		message = fmt.Sprintf("binding references cluster(s) \"%s\" that do not exist in namespace %s", missingClusters, arRequest.Request.Namespace)
-		zap.S().Errorw(message)
+		problem := newFieldProblem(missingField, problemTypeNotFound, "binding references cluster(s) \"%s\" that do not exist in namespace %s", missingClusters, arRequest.Request.Namespace)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
 	}
 
-	return message
+	return nil
 }
 
 // Validate that each secret in the binding has a matching secret in the default namespace
-func validateBindingSecrets(binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets) string {
+func validateBindingSecrets(ctx context.Context, binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets) []Problem {
 	zap.S().Debugw("In validateBindingSecrets code")
 
 	// Check database credentials
-	for _, dbBinding := range binding.Spec.DatabaseBindings {
-		message := getBindingSecrets(clientsets, dbBinding.Credentials, "databaseBindings.credentials", dbBinding.Name)
-		if message != "" {
-			return message
+	for i, dbBinding := range binding.Spec.DatabaseBindings {
+		field := fmt.Sprintf("spec.databaseBindings[%d].credentials", i)
+		if problems := getBindingSecrets(ctx, clientsets, dbBinding.Credentials, field, "databaseBindings.credentials", dbBinding.Name); len(problems) > 0 {
+			return problems
 		}
 	}
 
-	return ""
+	return nil
 }
 
 // Get a secret and check for errors
-func getBindingSecrets(clientsets *Clientsets, secretName string, secretType string, compName string) string {
+func getBindingSecrets(ctx context.Context, clientsets *Clientsets, secretName string, field string, secretType string, compName string) []Problem {
 	zap.S().Debugw("In getBindingSecrets code")
 
-	_, err := clientsets.K8sClient.CoreV1().Secrets("default").Get(context.TODO(), secretName, metav1.GetOptions{})
+	defer observeLookup(lookupGetSecret, time.Now())
+	_, err := clientsets.K8sClient.CoreV1().Secrets("default").Get(ctx, secretName, metav1.GetOptions{})
+	recordTimedOutLookup(ctx, err, fmt.Sprintf("secret %s of %s", secretName, compName))
 	if k8sErrors.IsNotFound(err) {
-		message := fmt.Sprintf("binding references %s \"%s\" for %s.  This secret must be created in the default namespace before proceeding.", secretType, secretName, compName)
-		zap.S().Errorw(message)
-		return message
+		problem := newFieldProblem(field, problemTypeNotFound, "binding references %s \"%s\" for %s.  This secret must be created in the default namespace before proceeding.", secretType, secretName, compName)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
 	}
 	if err != nil {
-		message := fmt.Sprintf("failed to get referenced secret %s in namespace default: %v", secretName, err)
-		zap.S().Errorw(message)
-		return message
+		problem := newFieldProblem(field, "", "failed to get referenced secret %s in namespace default: %v", secretName, err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
 	}
 
-	return ""
+	return nil
 }
@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -31,6 +32,22 @@ func TestValidateBinding(t *testing.T) {
 	cluster.Namespace = model.Namespace
 	cluster.Name = "local"
 	sec := newSecret("default", "mysql-credentials", "hello")
+	portsModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	portsModel.Spec.HelidonApplications[0].TargetPort = 8080
+	portsModel.Spec.HelidonApplications[1].TargetPort = 8080
+	samePlacement := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	samePlacement.Spec.Placement[0].Namespaces[0].Components = append(samePlacement.Spec.Placement[0].Namespaces[0].Components,
+		samePlacement.Spec.Placement[0].Namespaces[1].Components[0])
+	samePlacement.Spec.Placement[0].Namespaces[1].Components = samePlacement.Spec.Placement[0].Namespaces[1].Components[1:]
+	otherModel := &vzv1b.VerrazzanoModel{Spec: vzv1b.VerrazzanoModelSpec{
+		HelidonApplications: []vzv1b.VerrazzanoHelidon{{Name: "other-helidon-application", TargetPort: 8080}}}}
+	otherModel.Namespace = model.Namespace
+	otherModel.Name = "other-model"
+	otherBinding := &vzv1b.VerrazzanoBinding{Spec: vzv1b.VerrazzanoBindingSpec{ModelName: "other-model",
+		Placement: []vzv1b.VerrazzanoPlacement{{Name: "local", Namespaces: []vzv1b.KubernetesNamespace{
+			{Name: "robert", Components: []vzv1b.BindingComponent{{Name: "other-helidon-application"}}}}}}}}
+	otherBinding.Namespace = model.Namespace
+	otherBinding.Name = "other-binding"
 	tests := []struct {
 		name      string
 		k8sClient kubernetes.Interface
@@ -62,16 +79,35 @@ func TestValidateBinding(t *testing.T) {
 			v8oClient:             NewFakeVzClient(model, badBinding, cluster),
 			binding:               badBinding,
 			expectedErrorMessages: []string{"Multiple occurrence of component across placement namespaces"},
+		}, {
+			name:                  "TestValidateBindingModelError",
+			k8sClient:             fakek8s.NewSimpleClientset(sec),
+			v8oClient:             MockError(NewFakeVzClient(model, binding, cluster), "get", "verrazzanomodels", nil),
+			binding:               binding,
+			expectedErrorMessages: []string{"failed to get referenced model bobs-books-model in namespace default: Error get verrazzanomodels"},
+		}, {
+			name:                  "TestValidateBindingHelidonTargetPortCollision",
+			k8sClient:             fakek8s.NewSimpleClientset(sec),
+			v8oClient:             NewFakeVzClient(portsModel, samePlacement, cluster),
+			binding:               samePlacement,
+			expectedErrorMessages: []string{"spec.helidonApplications[1].targetPort: Duplicate value: 8080"},
+		}, {
+			name:      "TestValidateBindingHelidonTargetPortCollisionWithOtherModel",
+			k8sClient: fakek8s.NewSimpleClientset(sec),
+			v8oClient: NewFakeVzClient(portsModel, otherModel, otherBinding, cluster),
+			binding:   binding,
+			expectedErrorMessages: []string{"spec.helidonApplications[1].targetPort: Duplicate value: 8080: Helidon application roberts-helidon-stock-application uses the same target port as " +
+				"Helidon application other-helidon-application of model default/other-model in namespace robert of cluster local in binding other-binding"},
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			clientsets := &Clientsets{V8oClient: test.v8oClient, K8sClient: test.k8sClient}
-			admissionReview := validateBinding(review, *test.binding, clientsets, "myVerrazzanoURI")
+			problems := validateBinding(context.TODO(), review, *test.binding, clientsets, "myVerrazzanoURI", DefaultPolicy())
 			if len(test.expectedErrorMessages) == 0 {
-				assert.Nil(t, admissionReview.Response)
+				assert.Empty(t, problems)
 			} else {
-				errorMessage := admissionReview.Response.Result.Message
+				errorMessage := problemsMessage(problems)
 				for _, s := range test.expectedErrorMessages {
 					if !strings.Contains(errorMessage, s) {
 						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorMessages)
@@ -81,3 +117,152 @@ func TestValidateBinding(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateBindingModelRules tests the rules of the problems of the model referenced by a binding
+// GIVEN a binding whose model doesn't exist and a binding whose model can't be fetched
+//  WHEN validateBinding is called
+//  THEN the missing model should be reported by the binding-model rule and the lookup failure by the model-lookup rule
+func TestValidateBindingModelRules(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	review := kv1b.AdmissionReview{Request: &kv1b.AdmissionRequest{Namespace: model.Namespace}}
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+
+	clientsets := &Clientsets{V8oClient: NewFakeVzClient(), K8sClient: fakek8s.NewSimpleClientset()}
+	problems := validateBinding(context.TODO(), review, *binding, clientsets, "myVerrazzanoURI", DefaultPolicy())
+	assert.Len(t, problems, 1)
+	assert.Equal(t, ruleBindingModel, problems[0].Rule)
+	assert.Equal(t, problemTypeNotFound, problems[0].Type)
+
+	clientsets.V8oClient = MockError(NewFakeVzClient(model), "get", "verrazzanomodels", nil)
+	problems = validateBinding(context.TODO(), review, *binding, clientsets, "myVerrazzanoURI", DefaultPolicy())
+	assert.Len(t, problems, 1)
+	assert.Equal(t, ruleModelLookup, problems[0].Rule)
+	assert.False(t, isProtectedRule(problems[0].Rule))
+}
+
+// TestValidateBindingReplicas tests validation of the replicas of component bindings
+// GIVEN a VerrazzanoBinding and a policy
+//  WHEN validateBindingReplicas is called
+//  THEN the validation should produce messages containing the expected substrings
+func TestValidateBindingReplicas(t *testing.T) {
+	negative := int32(-1)
+	negativeBinding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	negativeBinding.Spec.HelidonBindings[1].Replicas = &negative
+	negativeBinding.Spec.WeblogicBindings = []vzv1b.VerrazzanoWeblogicBinding{{Name: "bobs-bookstore", Replicas: &negative}}
+	unsetBinding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	unsetBinding.Spec.WeblogicBindings = []vzv1b.VerrazzanoWeblogicBinding{{Name: "bobs-bookstore"}}
+	unsetBinding.Spec.CoherenceBindings = nil
+	unsetBinding.Spec.HelidonBindings = []vzv1b.VerrazzanoHelidonBinding{{Name: "bobbys-helidon-stock-application"}}
+	tests := []struct {
+		name                    string
+		binding                 *vzv1b.VerrazzanoBinding
+		policy                  Policy
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:    "TestValidReplicas",
+			binding: ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			policy:  Policy{MinBindingReplicas: 2, MaxBindingReplicas: 3},
+		}, {
+			name:    "TestNegativeReplicas",
+			binding: negativeBinding,
+			expectedErrorSubstrings: []string{"spec.weblogicBindings[0].replicas: Invalid value: -1: must be greater than or equal to 0",
+				"spec.helidonBindings[1].replicas: Invalid value: -1: must be greater than or equal to 0"},
+		}, {
+			name:    "TestMinReplicas",
+			binding: ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			policy:  Policy{MinBindingReplicas: 3},
+			expectedErrorSubstrings: []string{"spec.coherenceBindings[1].replicas: Invalid value: 2: must be greater than or equal to 3",
+				"spec.helidonBindings[0].replicas: Invalid value: 2: must be greater than or equal to 3"},
+		}, {
+			name:    "TestUnsetReplicas",
+			binding: unsetBinding,
+			policy:  Policy{MinBindingReplicas: 3, MaxBindingReplicas: 3},
+		}, {
+			name:    "TestMaxReplicas",
+			binding: ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			policy:  Policy{MaxBindingReplicas: 2},
+			expectedErrorSubstrings: []string{"spec.coherenceBindings[0].replicas: Invalid value: 3: must be less than or equal to 2",
+				"spec.helidonBindings[1].replicas: Invalid value: 3: must be less than or equal to 2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errMessages := validateBindingReplicas(*test.binding, test.policy)
+			assert.Equal(t, len(test.expectedErrorSubstrings), len(errMessages))
+			errorMessage := problemsMessage(errMessages)
+			for _, s := range test.expectedErrorSubstrings {
+				if !strings.Contains(errorMessage, s) {
+					t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateManagedClusterReplicas tests the limit of the replicas placed on a VerrazzanoManagedCluster
+// GIVEN a VerrazzanoBinding, other bindings with and without replicas in the same and other namespaces and a policy
+//  WHEN validateManagedClusterReplicas is called
+//  THEN the validation should produce messages containing the expected substrings
+func TestValidateManagedClusterReplicas(t *testing.T) {
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	review := kv1b.AdmissionReview{Request: &kv1b.AdmissionRequest{Namespace: binding.Namespace}}
+	other := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	other.Name = "other-binding"
+	otherNamespace := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	otherNamespace.Namespace = "bob"
+	unsetReplicas := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	unsetReplicas.Name = "unset-replicas-binding"
+	for i := range unsetReplicas.Spec.CoherenceBindings {
+		unsetReplicas.Spec.CoherenceBindings[i].Replicas = nil
+	}
+	for i := range unsetReplicas.Spec.HelidonBindings {
+		unsetReplicas.Spec.HelidonBindings[i].Replicas = nil
+	}
+	tests := []struct {
+		name                    string
+		v8oClient               v8oclientset.VerrazzanoV1beta1Interface
+		policy                  Policy
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:      "TestNoLimit",
+			v8oClient: NewFakeVzClient(binding, other),
+		}, {
+			name:      "TestUpdatedBindingWithinLimit",
+			v8oClient: NewFakeVzClient(binding),
+			policy:    Policy{MaxManagedClusterReplicas: 10},
+		}, {
+			name:                    "TestOtherBindingExceedsLimit",
+			v8oClient:               NewFakeVzClient(binding, other),
+			policy:                  Policy{MaxManagedClusterReplicas: 19},
+			expectedErrorSubstrings: []string{"spec.placement[0].name: Forbidden: bindings place 20 replicas on cluster local in namespace default, the maximum allowed is 19"},
+		}, {
+			name:      "TestBindingOnClusterOfOtherNamespace",
+			v8oClient: NewFakeVzClient(binding, otherNamespace),
+			policy:    Policy{MaxManagedClusterReplicas: 19},
+		}, {
+			name:      "TestOtherBindingWithoutReplicas",
+			v8oClient: NewFakeVzClient(binding, unsetReplicas),
+			policy:    Policy{MaxManagedClusterReplicas: 10},
+		}, {
+			name:                    "TestListError",
+			v8oClient:               MockError(NewFakeVzClient(binding), "list", "verrazzanobindings", &vzv1b.VerrazzanoBindingList{}),
+			policy:                  Policy{MaxManagedClusterReplicas: 10},
+			expectedErrorSubstrings: []string{"failed to list bindings in all namespaces"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientsets := &Clientsets{V8oClient: test.v8oClient}
+			errMessages := validateManagedClusterReplicas(context.TODO(), review, *binding, clientsets, test.policy)
+			assert.Equal(t, len(test.expectedErrorSubstrings), len(errMessages))
+			errorMessage := problemsMessage(errMessages)
+			for _, s := range test.expectedErrorSubstrings {
+				if !strings.Contains(errorMessage, s) {
+					t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	s "strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Cause types of the problem types reported by the validations
+var problemCauseTypes = map[string]metav1.CauseType{
+	problemTypeInvalid:      metav1.CauseTypeFieldValueInvalid,
+	problemTypeDuplicate:    metav1.CauseTypeFieldValueDuplicate,
+	problemTypeRequired:     metav1.CauseTypeFieldValueRequired,
+	problemTypeNotSupported: metav1.CauseTypeFieldValueNotSupported,
+	problemTypeForbidden:    metav1.CauseType(field.ErrorTypeForbidden),
+	problemTypeNotFound:     metav1.CauseTypeFieldValueNotFound,
+}
+
+// Prefix the API server adds to the message of a request denied by an admission webhook
+const deniedRequestMarker = "denied the request: "
+
+// Get the causes of the problems of a validation, one for each problem, so that clients don't have to parse the
+// message
+func statusCauses(problems []Problem) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	for _, problem := range problems {
+		cause := metav1.StatusCause{Type: problemCauseTypes[problem.Type], Message: problem.Message, Field: problem.Field}
+		if problem.Field != "" {
+			cause.Message = s.TrimPrefix(problem.Message, problem.Field+": ")
+		}
+		causes = append(causes, cause)
+	}
+	return causes
+}
+
+// ProblemsFromStatus gets the problems of a request denied by the webhook from the causes of the status.  A status
+// without causes, like the status of a webhook of a previous version, has a single problem with its message.
+func ProblemsFromStatus(status metav1.Status) []Problem {
+	if status.Details == nil || len(status.Details.Causes) == 0 {
+		message := status.Message
+		if i := s.Index(message, deniedRequestMarker); i >= 0 {
+			message = message[i+len(deniedRequestMarker):]
+		}
+		return []Problem{{Message: message}}
+	}
+
+	var problems []Problem
+	for _, cause := range status.Details.Causes {
+		problem := Problem{Field: cause.Field, Message: cause.Message}
+		for problemType, causeType := range problemCauseTypes {
+			if cause.Type == causeType {
+				problem.Type = problemType
+			}
+		}
+		if cause.Field != "" {
+			problem.Message = cause.Field + ": " + cause.Message
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Problems of a validation used by the cause tests
+var causesProblems = []Problem{
+	fieldProblem("spec.placement[0].namespaces[1].name", problemTypeInvalid, "\"bad_name\": not valid"),
+	fieldProblem("spec.databaseBindings[0]", problemTypeForbidden,
+		"database host db.example.com of database binding mysql is not allowed by policy, the host must be in the cluster"),
+	newProblem("binding references cluster(s) \"local\" that do not exist in namespace default"),
+}
+
+// TestErrorAdmissionReviewCauses tests the causes of a denied admission review
+// GIVEN the problems of a validation
+//  WHEN errorAdmissionReview is called
+//  THEN the status should have the message of the problems and a cause for each problem
+func TestErrorAdmissionReviewCauses(t *testing.T) {
+	result := errorAdmissionReview(causesProblems).Response.Result
+	assert.Equal(t, "spec.placement[0].namespaces[1].name: Invalid value: \"bad_name\": not valid; "+
+		"spec.databaseBindings[0]: Forbidden: database host db.example.com of database binding mysql is not allowed by policy, the host must be in the cluster; "+
+		"binding references cluster(s) \"local\" that do not exist in namespace default", result.Message)
+	assert.Equal(t, []metav1.StatusCause{
+		{Type: metav1.CauseTypeFieldValueInvalid, Field: "spec.placement[0].namespaces[1].name", Message: "Invalid value: \"bad_name\": not valid"},
+		{Type: "FieldValueForbidden", Field: "spec.databaseBindings[0]",
+			Message: "Forbidden: database host db.example.com of database binding mysql is not allowed by policy, the host must be in the cluster"},
+		{Message: "binding references cluster(s) \"local\" that do not exist in namespace default"},
+	}, result.Details.Causes)
+}
+
+// TestProblemsFromStatus tests getting the problems of a request denied by the webhook
+// GIVEN the status of a request denied by the webhook, with or without causes
+//  WHEN ProblemsFromStatus is called
+//  THEN the problems of the causes should be returned, or a single problem with the message without causes
+func TestProblemsFromStatus(t *testing.T) {
+	status := *errorAdmissionReview(causesProblems).Response.Result
+	status.Message = "admission webhook \"verrazzano-validation.verrazzano.io\" denied the request: " + status.Message
+	assert.Equal(t, causesProblems, ProblemsFromStatus(status))
+
+	status.Details = nil
+	assert.Equal(t, []Problem{{Message: problemsMessage(causesProblems)}}, ProblemsFromStatus(status))
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// CertificateReloader serves the key pair of a certificate file and a key file, and reloads it when the files
+// change so that rotated certificates are used without a restart.  A reloader without files serves the key pair
+// given to SetKeyPair.
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+// NewCertificateReloader returns a reloader for the key pair of the given files, which must be loadable
+func NewCertificateReloader(certFile string, keyFile string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// GetCertificate returns the current certificate, it is meant to be used as tls.Config.GetCertificate
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certificate, _ := r.Certificate()
+	if certificate == nil {
+		return nil, errors.New("no certificate loaded")
+	}
+	return certificate, nil
+}
+
+// Certificate returns the current certificate
+func (r *CertificateReloader) Certificate() (*tls.Certificate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.certificate, nil
+}
+
+// Reload loads the key pair if the files changed since they were last loaded.  Returns whether a new
+// certificate was loaded.  The current certificate is kept when the files can't be loaded, for example while
+// only one of them has been updated.
+func (r *CertificateReloader) Reload() (bool, error) {
+	certPEM, err := ioutil.ReadFile(r.certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read certificate file %s: %v", r.certFile, err)
+	}
+	keyPEM, err := ioutil.ReadFile(r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read key file %s: %v", r.keyFile, err)
+	}
+
+	r.mutex.RLock()
+	unchanged := bytes.Equal(certPEM, r.certPEM) && bytes.Equal(keyPEM, r.keyPEM)
+	r.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	if err := r.SetKeyPair(certPEM, keyPEM); err != nil {
+		return false, fmt.Errorf("failed to load key pair %s and %s: %v", r.certFile, r.keyFile, err)
+	}
+	return true, nil
+}
+
+// SetKeyPair replaces the current certificate by a PEM encoded key pair
+func (r *CertificateReloader) SetKeyPair(certPEM []byte, keyPEM []byte) error {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	if certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0]); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	r.certificate, r.certPEM, r.keyPEM = &certificate, certPEM, keyPEM
+	r.mutex.Unlock()
+	zap.S().Infof("Loaded certificate with serial number %s, valid until %s", certificate.Leaf.SerialNumber.String(),
+		certificate.Leaf.NotAfter.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// Watch checks the files for changes at the given interval and reloads the key pair when they change, until
+// the stop channel is closed
+func (r *CertificateReloader) Watch(interval time.Duration, stopCh <-chan struct{}) {
+	zap.S().Infof("Watching certificate %s and key %s for changes", r.certFile, r.keyFile)
+	go wait.Until(func() {
+		if _, err := r.Reload(); err != nil {
+			zap.S().Errorf("Failed to reload certificate, using the current certificate: %v", err)
+		}
+	}, interval, stopCh)
+}
+
+// CABundleFile serves the CA bundle of a file, and reloads it when the file changes so that a rotated CA is
+// injected in the webhook configuration
+type CABundleFile struct {
+	caFile string
+
+	mutex    sync.RWMutex
+	caBundle []byte
+}
+
+// NewCABundleFile returns a CA bundle for the given file, which must contain a certificate
+func NewCABundleFile(caFile string) (*CABundleFile, error) {
+	bundle := &CABundleFile{caFile: caFile}
+	if _, err := bundle.Reload(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// CABundle returns the current PEM encoded CA bundle
+func (b *CABundleFile) CABundle() []byte {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.caBundle
+}
+
+// Reload loads the CA bundle if the file changed since it was last loaded.  Returns whether a new bundle was
+// loaded.  The current bundle is kept when the file doesn't contain a certificate.
+func (b *CABundleFile) Reload() (bool, error) {
+	caBundle, err := ioutil.ReadFile(b.caFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read CA file %s: %v", b.caFile, err)
+	}
+
+	b.mutex.RLock()
+	unchanged := bytes.Equal(caBundle, b.caBundle)
+	b.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+		return false, fmt.Errorf("CA file %s doesn't contain a PEM encoded certificate", b.caFile)
+	}
+	b.mutex.Lock()
+	b.caBundle = caBundle
+	b.mutex.Unlock()
+	zap.S().Infof("Loaded CA bundle %s", b.caFile)
+	return true, nil
+}
+
+// Watch checks the file for changes at the given interval and calls onChange after a new bundle is loaded, until
+// the stop channel is closed
+func (b *CABundleFile) Watch(interval time.Duration, onChange func(), stopCh <-chan struct{}) {
+	zap.S().Infof("Watching CA bundle %s for changes", b.caFile)
+	go wait.Until(func() {
+		reloaded, err := b.Reload()
+		if err != nil {
+			zap.S().Errorf("Failed to reload CA bundle, using the current CA bundle: %v", err)
+			return
+		}
+		if reloaded && onChange != nil {
+			onChange()
+		}
+	}, interval, stopCh)
+}
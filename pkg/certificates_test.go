@@ -0,0 +1,139 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Write the key pair of a certificate to PEM files
+func writeKeyPair(t *testing.T, certificate *tls.Certificate, certFile string, keyFile string) {
+	keyDER, err := x509.MarshalECPrivateKey(certificate.PrivateKey.(*ecdsa.PrivateKey))
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]}), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+// TestCertificateReloader tests reloading of certificates
+// GIVEN certificate and key files
+//  WHEN the files are unchanged, replaced by a new key pair and replaced by a key pair that can't be loaded
+//  THEN the certificate should only be reloaded when the files contain a new key pair
+func TestCertificateReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	_, err = NewCertificateReloader(certFile, keyFile)
+	assert.NotNil(t, err, "the key pair must exist")
+
+	now := time.Now()
+	first := newTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour))
+	writeKeyPair(t, first, certFile, keyFile)
+	reloader, err := NewCertificateReloader(certFile, keyFile)
+	assert.Nil(t, err)
+	certificate, err := reloader.GetCertificate(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, first.Certificate, certificate.Certificate)
+	assert.NotNil(t, certificate.Leaf)
+
+	reloaded, err := reloader.Reload()
+	assert.Nil(t, err)
+	assert.False(t, reloaded)
+
+	second := newTestCertificate(t, now.Add(-time.Hour), now.Add(2*time.Hour))
+	writeKeyPair(t, second, certFile, keyFile)
+	reloaded, err = reloader.Reload()
+	assert.Nil(t, err)
+	assert.True(t, reloaded)
+	certificate, _ = reloader.Certificate()
+	assert.Equal(t, second.Certificate, certificate.Certificate)
+
+	// Only the certificate has been updated, the key doesn't match
+	third := newTestCertificate(t, now.Add(-time.Hour), now.Add(3*time.Hour))
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: third.Certificate[0]}), 0600))
+	reloaded, err = reloader.Reload()
+	assert.NotNil(t, err)
+	assert.False(t, reloaded)
+	certificate, _ = reloader.Certificate()
+	assert.Equal(t, second.Certificate, certificate.Certificate)
+}
+
+// TestCertificateReloaderWatch tests watching of certificate files
+// GIVEN a reloader watching certificate and key files
+//  WHEN the files are replaced by a new key pair
+//  THEN the new certificate should be served
+func TestCertificateReloaderWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	now := time.Now()
+	writeKeyPair(t, newTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour)), certFile, keyFile)
+	reloader, err := NewCertificateReloader(certFile, keyFile)
+	assert.Nil(t, err)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	reloader.Watch(10*time.Millisecond, stopCh)
+
+	rotated := newTestCertificate(t, now.Add(-time.Hour), now.Add(2*time.Hour))
+	writeKeyPair(t, rotated, certFile, keyFile)
+	assert.Eventually(t, func() bool {
+		certificate, _ := reloader.Certificate()
+		return bytes.Equal(certificate.Certificate[0], rotated.Certificate[0])
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestCABundleFileWatch tests watching of a CA file
+// GIVEN a CA bundle watching a CA file
+//  WHEN the file is replaced by a new CA, then by a file without a certificate
+//  THEN the new CA should be served and the change notified, and the CA be kept when the file is not valid
+func TestCABundleFileWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.pem")
+
+	_, err = NewCABundleFile(caFile)
+	assert.NotNil(t, err, "the CA file must exist")
+
+	now := time.Now()
+	first := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour)).Certificate[0]})
+	assert.Nil(t, ioutil.WriteFile(caFile, first, 0600))
+	bundle, err := NewCABundleFile(caFile)
+	assert.Nil(t, err)
+	assert.Equal(t, first, bundle.CABundle())
+
+	changes := make(chan struct{}, 10)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	bundle.Watch(10*time.Millisecond, func() { changes <- struct{}{} }, stopCh)
+
+	rotated := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newTestCertificate(t, now.Add(-time.Hour), now.Add(2*time.Hour)).Certificate[0]})
+	assert.Nil(t, ioutil.WriteFile(caFile, rotated, 0600))
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change of the CA file should be notified")
+	}
+	assert.Equal(t, rotated, bundle.CABundle())
+
+	assert.Nil(t, ioutil.WriteFile(caFile, []byte("not a certificate"), 0600))
+	reloaded, err := bundle.Reload()
+	assert.NotNil(t, err)
+	assert.False(t, reloaded)
+	assert.Equal(t, rotated, bundle.CABundle())
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	s "strings"
+
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	"go.uber.org/zap"
+	k8sValidations "k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	jdbcMySQL      = "mysql"
+	jdbcOracleThin = "oracle:thin"
+	jdbcPostgreSQL = "postgresql"
+
+	// Domain of the Kubernetes cluster used in service DNS names
+	clusterDomain = "cluster.local"
+)
+
+// Ports used when a JDBC URL doesn't contain a port, keyed by JDBC flavor
+var defaultDatabasePorts = map[string]int{
+	jdbcMySQL:      3306,
+	jdbcOracleThin: 1521,
+	jdbcPostgreSQL: 5432,
+}
+
+// jdbcURL contains the parts of a JDBC URL needed for validation
+type jdbcURL struct {
+	flavor   string
+	host     string
+	port     int
+	database string
+}
+
+// Parse a JDBC URL of one of the supported flavors:
+//
+//	jdbc:mysql://host[:port]/database[?properties]
+//	jdbc:postgresql://host[:port]/database[?properties]
+//	jdbc:oracle:thin:@[//]host[:port]/service or jdbc:oracle:thin:@host[:port]:SID
+func parseJDBCURL(url string) (*jdbcURL, error) {
+	switch {
+	case s.HasPrefix(url, "jdbc:"+jdbcMySQL+"://"):
+		return parseJDBCHostURL(jdbcMySQL, s.TrimPrefix(url, "jdbc:"+jdbcMySQL+"://"))
+	case s.HasPrefix(url, "jdbc:"+jdbcPostgreSQL+"://"):
+		return parseJDBCHostURL(jdbcPostgreSQL, s.TrimPrefix(url, "jdbc:"+jdbcPostgreSQL+"://"))
+	case s.HasPrefix(url, "jdbc:"+jdbcOracleThin+":@"):
+		return parseOracleThinURL(s.TrimPrefix(url, "jdbc:"+jdbcOracleThin+":@"))
+	}
+	return nil, fmt.Errorf("unsupported JDBC URL, supported URLs start with jdbc:mysql://, jdbc:postgresql:// or jdbc:oracle:thin:@")
+}
+
+// Parse the host[:port]/database[?properties] part of a MySQL or PostgreSQL URL
+func parseJDBCHostURL(flavor string, rest string) (*jdbcURL, error) {
+	if i := s.IndexAny(rest, "?;"); i >= 0 {
+		rest = rest[:i]
+	}
+	slash := s.Index(rest, "/")
+	if slash < 0 {
+		return nil, fmt.Errorf("database name is missing, expected jdbc:%s://host[:port]/database", flavor)
+	}
+	url := &jdbcURL{flavor: flavor, database: rest[slash+1:]}
+	if err := url.setHostPort(rest[:slash]); err != nil {
+		return nil, err
+	}
+	if url.database == "" || s.Contains(url.database, "/") {
+		return nil, fmt.Errorf("invalid database name \"%s\"", url.database)
+	}
+	return url, nil
+}
+
+// Parse the part of an Oracle thin URL after the @
+func parseOracleThinURL(rest string) (*jdbcURL, error) {
+	if s.HasPrefix(rest, "(") {
+		return nil, fmt.Errorf("TNS connect descriptors are not supported, expected jdbc:oracle:thin:@//host[:port]/service")
+	}
+	rest = s.TrimPrefix(rest, "//")
+	url := &jdbcURL{flavor: jdbcOracleThin}
+
+	// host[:port]/service or host:port:SID
+	hostPort := rest
+	if slash := s.Index(rest, "/"); slash >= 0 {
+		hostPort, url.database = rest[:slash], rest[slash+1:]
+	} else if parts := s.Split(rest, ":"); len(parts) == 3 {
+		hostPort, url.database = parts[0]+":"+parts[1], parts[2]
+	}
+	if url.database == "" {
+		return nil, fmt.Errorf("service name or SID is missing, expected jdbc:oracle:thin:@//host[:port]/service or jdbc:oracle:thin:@host:port:SID")
+	}
+	if err := url.setHostPort(hostPort); err != nil {
+		return nil, err
+	}
+	return url, nil
+}
+
+// Set the host and port from host[:port], using the default port of the flavor when there is none
+func (u *jdbcURL) setHostPort(hostPort string) error {
+	u.host, u.port = hostPort, defaultDatabasePorts[u.flavor]
+	if s.Contains(hostPort, ":") {
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return fmt.Errorf("invalid host and port \"%s\": %v", hostPort, err)
+		}
+		portNum, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("invalid port \"%s\"", port)
+		}
+		u.host, u.port = host, portNum
+	}
+	if u.host == "" {
+		return fmt.Errorf("host is missing")
+	}
+	if msgs := k8sValidations.IsValidPortNum(u.port); len(msgs) > 0 {
+		return fmt.Errorf("port %d is not valid. %s", u.port, s.Join(msgs, ", "))
+	}
+	return nil
+}
+
+// Check if a host is the DNS name of a service in the cluster: service, service.namespace,
+// service.namespace.svc or service.namespace.svc.cluster.local
+func isClusterServiceHost(host string) bool {
+	labels := s.Split(s.TrimSuffix(host, "."+clusterDomain), ".")
+	if len(labels) > 3 || (len(labels) == 3 && labels[2] != "svc") {
+		return false
+	}
+	if len(labels) < 3 && s.HasSuffix(host, "."+clusterDomain) {
+		return false
+	}
+	for _, label := range labels {
+		if len(k8sValidations.IsDNS1123Label(label)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if a database host is allowed by the policy
+func isDatabaseHostAllowed(host string, policy Policy) bool {
+	if !policy.DatabaseHostsInCluster && len(policy.AllowedDatabaseDomains) == 0 {
+		return true
+	}
+	if policy.DatabaseHostsInCluster && isClusterServiceHost(host) {
+		return true
+	}
+	for _, domain := range policy.AllowedDatabaseDomains {
+		domain = s.TrimPrefix(domain, ".")
+		if host == domain || s.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate the URLs of the database bindings
+func validateDatabaseBindings(binding v1beta1v8o.VerrazzanoBinding, policy Policy) []Problem {
+	zap.S().Debugw("In validateDatabaseBindings code")
+
+	var errMessages []Problem
+	for i, dbBinding := range binding.Spec.DatabaseBindings {
+		field := fmt.Sprintf("spec.databaseBindings[%d].url", i)
+		if dbBinding.Url == "" {
+			errMessages = append(errMessages, fieldProblem(field, problemTypeRequired, fmt.Sprintf("database binding %s must have a URL", dbBinding.Name)))
+			continue
+		}
+		url, err := parseJDBCURL(dbBinding.Url)
+		if err != nil {
+			errMessages = append(errMessages, fieldProblem(field, problemTypeInvalid, fmt.Sprintf("\"%s\": %v", dbBinding.Url, err)))
+			continue
+		}
+		if !isDatabaseHostAllowed(url.host, policy) {
+			errMessages = append(errMessages, fieldProblem(field, problemTypeForbidden,
+				fmt.Sprintf("database host %s of database binding %s is not allowed by policy, %s", url.host, dbBinding.Name, describeDatabaseHostPolicy(policy))))
+		}
+	}
+
+	if len(errMessages) > 0 {
+		zap.S().Errorw(problemsMessage(errMessages))
+	}
+	return errMessages
+}
+
+// Describe the database hosts allowed by the policy
+func describeDatabaseHostPolicy(policy Policy) string {
+	var allowed []string
+	if policy.DatabaseHostsInCluster {
+		allowed = append(allowed, "service DNS names in the cluster")
+	}
+	if len(policy.AllowedDatabaseDomains) > 0 {
+		allowed = append(allowed, "hosts in domains "+s.Join(policy.AllowedDatabaseDomains, ", "))
+	}
+	return "allowed hosts are " + s.Join(allowed, " and ")
+}
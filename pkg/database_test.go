@@ -0,0 +1,108 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzv1b "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+)
+
+// TestParseJDBCURL tests parsing of JDBC URLs
+// GIVEN a JDBC URL
+//  WHEN parseJDBCURL is called with the URL
+//  THEN the host, port and database should be extracted or an error returned
+func TestParseJDBCURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		expected    *jdbcURL
+		expectedErr string
+	}{
+		{url: "jdbc:mysql://mysql.bob.svc.cluster.local:3306/books", expected: &jdbcURL{flavor: jdbcMySQL, host: "mysql.bob.svc.cluster.local", port: 3306, database: "books"}},
+		{url: "jdbc:mysql://mysql/books?useSSL=false", expected: &jdbcURL{flavor: jdbcMySQL, host: "mysql", port: 3306, database: "books"}},
+		{url: "jdbc:postgresql://db.example.com/orders", expected: &jdbcURL{flavor: jdbcPostgreSQL, host: "db.example.com", port: 5432, database: "orders"}},
+		{url: "jdbc:oracle:thin:@//db.example.com:1522/orclpdb1", expected: &jdbcURL{flavor: jdbcOracleThin, host: "db.example.com", port: 1522, database: "orclpdb1"}},
+		{url: "jdbc:oracle:thin:@db.example.com/orclpdb1", expected: &jdbcURL{flavor: jdbcOracleThin, host: "db.example.com", port: 1521, database: "orclpdb1"}},
+		{url: "jdbc:oracle:thin:@db.example.com:1521:ORCL", expected: &jdbcURL{flavor: jdbcOracleThin, host: "db.example.com", port: 1521, database: "ORCL"}},
+		{url: "jdbc:sqlserver://db.example.com:1433", expectedErr: "unsupported JDBC URL"},
+		{url: "jdbc:mysql://mysql:3306", expectedErr: "database name is missing"},
+		{url: "jdbc:mysql://mysql:70000/books", expectedErr: "port 70000 is not valid"},
+		{url: "jdbc:mysql://mysql:port/books", expectedErr: "invalid port \"port\""},
+		{url: "jdbc:postgresql://:5432/orders", expectedErr: "host is missing"},
+		{url: "jdbc:oracle:thin:@(DESCRIPTION=(ADDRESS=(HOST=db)(PORT=1521)))", expectedErr: "TNS connect descriptors are not supported"},
+		{url: "jdbc:oracle:thin:@db.example.com", expectedErr: "service name or SID is missing"},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			url, err := parseJDBCURL(test.url)
+			if test.expectedErr != "" {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, url)
+			}
+		})
+	}
+}
+
+// TestIsDatabaseHostAllowed tests the database host policy
+// GIVEN a database host and a policy
+//  WHEN isDatabaseHostAllowed is called
+//  THEN the host should only be allowed if it matches the policy
+func TestIsDatabaseHostAllowed(t *testing.T) {
+	inCluster := Policy{DatabaseHostsInCluster: true}
+	domains := Policy{AllowedDatabaseDomains: []string{"example.com"}}
+	assert.True(t, isDatabaseHostAllowed("db.anywhere.org", Policy{}))
+	assert.True(t, isDatabaseHostAllowed("mysql.bob.svc.cluster.local", inCluster))
+	assert.True(t, isDatabaseHostAllowed("mysql.bob.svc", inCluster))
+	assert.True(t, isDatabaseHostAllowed("mysql", inCluster))
+	assert.False(t, isDatabaseHostAllowed("mysql.bob.svc.default.local", inCluster))
+	assert.False(t, isDatabaseHostAllowed("db.example.com", inCluster))
+	assert.True(t, isDatabaseHostAllowed("db.example.com", domains))
+	assert.True(t, isDatabaseHostAllowed("example.com", domains))
+	assert.False(t, isDatabaseHostAllowed("db.badexample.com", domains))
+	assert.False(t, isDatabaseHostAllowed("mysql", domains))
+}
+
+// TestValidateDatabaseBindings tests validation of database binding URLs
+// GIVEN a VerrazzanoBinding with database bindings and a policy
+//  WHEN validateDatabaseBindings is called
+//  THEN the validation should produce messages containing the expected substrings
+func TestValidateDatabaseBindings(t *testing.T) {
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	binding.Spec.DatabaseBindings = append(binding.Spec.DatabaseBindings,
+		vzv1b.VerrazzanoDatabaseBinding{Name: "orders", Credentials: "orders-credentials", Url: "jdbc:postgresql://orders.example.com/orders"},
+		vzv1b.VerrazzanoDatabaseBinding{Name: "legacy", Credentials: "legacy-credentials", Url: "jdbc:db2://legacy:50000/legacy"},
+		vzv1b.VerrazzanoDatabaseBinding{Name: "empty", Credentials: "empty-credentials"})
+
+	tests := []struct {
+		name                    string
+		policy                  Policy
+		expectedErrorSubstrings []string
+	}{
+		{
+			name: "TestNoHostPolicy",
+			expectedErrorSubstrings: []string{"spec.databaseBindings[2].url: Invalid value: \"jdbc:db2://legacy:50000/legacy\": unsupported JDBC URL",
+				"spec.databaseBindings[3].url: Required value"},
+		}, {
+			name:                    "TestInClusterHostPolicy",
+			policy:                  Policy{DatabaseHostsInCluster: true},
+			expectedErrorSubstrings: []string{"spec.databaseBindings[1].url: Forbidden: database host orders.example.com of database binding orders is not allowed by policy, allowed hosts are service DNS names in the cluster"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessage := problemsMessage(validateDatabaseBindings(*binding, test.policy))
+			assert.NotContains(t, errorMessage, "spec.databaseBindings[0]")
+			for _, s := range test.expectedErrorSubstrings {
+				if !strings.Contains(errorMessage, s) {
+					t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	s "strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+)
+
+// Key of the timeout tracker in the context of a validation
+type timeoutTrackerKey struct{}
+
+// Records the first lookup of a validation that failed because the deadline of the validation was exceeded
+type timeoutTracker struct {
+	mutex    sync.Mutex
+	checking string
+}
+
+// Get the lookup that failed because the deadline was exceeded, empty if the validation didn't time out
+func (t *timeoutTracker) timedOutChecking() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.checking
+}
+
+// Record what a lookup was checking if it failed because the deadline of its validation was exceeded.  Called
+// after each API lookup with its error so that a timeout is reported with what was being checked.  Lookups that
+// succeed are not recorded, even when the deadline is exceeded after they complete.
+func recordTimedOutLookup(ctx context.Context, err error, checking string) {
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return
+	}
+	tracker, ok := ctx.Value(timeoutTrackerKey{}).(*timeoutTracker)
+	if !ok {
+		return
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if tracker.checking == "" {
+		tracker.checking = checking
+	}
+}
+
+// Run the validators of a request with a deadline, no deadline if the timeout is zero.  When a lookup fails because
+// the deadline was exceeded, the request is denied with what the lookup was checking, or allowed if failOpen is set.
+func validateWithDeadline(ctx context.Context, validators *ValidatorRegistry, request *ValidatorRequest, timeout time.Duration, failOpen bool) ValidatorResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	tracker := &timeoutTracker{}
+	result := validators.Validate(context.WithValue(ctx, timeoutTrackerKey{}, tracker), request)
+
+	checking := tracker.timedOutChecking()
+	if checking == "" {
+		return result
+	}
+	validationTimeouts.WithLabelValues(request.Kind.Kind).Inc()
+	if failOpen {
+		zap.S().Warnf("validation of %s %s:%s timed out after %v while checking %s, allowing the request", request.Kind.Kind,
+			request.Namespace, request.Name, timeout, checking)
+		return Allow()
+	}
+	problem := newProblem("validation timed out while checking %s", checking)
+	zap.S().Errorw(problem.Message)
+	return DenyProblems(withRule(ruleValidationTimeout, []Problem{problem})...)
+}
+
+// Get the failure policy and the timeout of the webhook of a kind, from the registration of the kind and the
+// overrides of the webhooks
+func webhookSettings(registration ValidatorRegistration, overrides map[string]WebhookOverride) (admissionv1beta1.FailurePolicyType, int32) {
+	failurePolicy := registration.FailurePolicy
+	timeoutSeconds := registration.TimeoutSeconds
+	if override, ok := overrides[s.ToLower(registration.Kind)]; ok {
+		if override.FailurePolicy != "" {
+			failurePolicy = override.FailurePolicy
+		}
+		if override.TimeoutSeconds != 0 {
+			timeoutSeconds = override.TimeoutSeconds
+		}
+	}
+	return failurePolicy, timeoutSeconds
+}
+
+// Get the deadline of the validation of a request of a registration and whether the request is allowed when the
+// deadline is exceeded.  The validation gets 80% of the timeout of the webhook, so that the webhook reports the
+// timeout before the API server gives up, and at most maxTimeout if set.  Requests are allowed like the failure
+// policy of the webhook, or if failOpen is set.
+func validationDeadline(registration ValidatorRegistration, overrides map[string]WebhookOverride, maxTimeout time.Duration, failOpen bool) (time.Duration, bool) {
+	failurePolicy, timeoutSeconds := webhookSettings(registration, overrides)
+	timeout := time.Duration(timeoutSeconds) * time.Second * 4 / 5
+	if maxTimeout > 0 && maxTimeout < timeout {
+		timeout = maxTimeout
+	}
+	return timeout, failOpen || failurePolicy == admissionv1beta1.Ignore
+}
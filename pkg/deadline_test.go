@@ -0,0 +1,150 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// Validator of models used by the tests, which looks up a secret
+type testSecretLookupValidator struct{}
+
+func (testSecretLookupValidator) Registration() ValidatorRegistration {
+	return newTestModelNameValidator("", admissionv1beta1.Create).registration
+}
+
+func (testSecretLookupValidator) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	return problemsResult(getSecret(ctx, request.Clientsets, "ocr", "helidonApplications.imagePullSecret", "helidon-app"))
+}
+
+// TestValidateWithDeadline tests validation of requests whose lookups exceed the deadline
+// GIVEN a validator looking up a secret with an API server taking 200ms to respond
+//  WHEN a request is validated with deadlines shorter and longer than the response time
+//  THEN the request should be denied with the secret being checked when the lookup fails because of the deadline, or allowed if failing open or if the lookup succeeds
+func TestValidateWithDeadline(t *testing.T) {
+	registry := &ValidatorRegistry{validators: []Validator{testSecretLookupValidator{}}}
+
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		failOpen bool
+		// Error returned by the lookup, the fake client doesn't fail when the deadline is exceeded
+		lookupErr       error
+		expectedMessage string
+		timedOut        bool
+	}{
+		{
+			name:            "TestTimedOut",
+			timeout:         50 * time.Millisecond,
+			lookupErr:       context.DeadlineExceeded,
+			expectedMessage: "validation timed out while checking secret ocr of component helidon-app",
+			timedOut:        true,
+		}, {
+			name:      "TestTimedOutFailOpen",
+			timeout:   50 * time.Millisecond,
+			failOpen:  true,
+			lookupErr: context.DeadlineExceeded,
+			timedOut:  true,
+		}, {
+			name:    "TestSucceededAfterDeadline",
+			timeout: 50 * time.Millisecond,
+		}, {
+			name:    "TestWithinDeadline",
+			timeout: 5 * time.Second,
+		}, {
+			name: "TestWithoutDeadline",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset(newImagePullSecret("default", "ocr"))
+			k8sClient.PrependReactor("get", "secrets", func(action ktesting.Action) (bool, runtime.Object, error) {
+				time.Sleep(200 * time.Millisecond)
+				return test.lookupErr != nil, nil, test.lookupErr
+			})
+			timeouts := testutil.ToFloat64(validationTimeouts.WithLabelValues("VerrazzanoModel"))
+			result := validateWithDeadline(context.TODO(), registry, &ValidatorRequest{
+				AdmissionRequest: &v1beta1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: "VerrazzanoModel"}, Operation: v1beta1.Create},
+				Clientsets:       &Clientsets{K8sClient: k8sClient},
+			}, test.timeout, test.failOpen)
+			assert.Equal(t, test.expectedMessage == "", result.Allowed)
+			assert.Equal(t, test.expectedMessage, result.Message)
+			if test.timedOut {
+				assert.Equal(t, timeouts+1, testutil.ToFloat64(validationTimeouts.WithLabelValues("VerrazzanoModel")))
+			} else {
+				assert.Equal(t, timeouts, testutil.ToFloat64(validationTimeouts.WithLabelValues("VerrazzanoModel")))
+			}
+		})
+	}
+}
+
+// TestValidationDeadline tests computation of the deadline of the validation of a request
+// GIVEN the registrations of models and secrets with and without overrides of their webhooks
+//  WHEN the deadline of the validation of their requests is computed
+//  THEN the deadline should be at most 80% of the webhook timeout and failures should be ignored like the webhook
+func TestValidationDeadline(t *testing.T) {
+	model, _ := DefaultValidators.Registration("VerrazzanoModel")
+	secret, _ := DefaultValidators.Registration("Secret")
+
+	tests := []struct {
+		name             string
+		registration     ValidatorRegistration
+		overrides        map[string]WebhookOverride
+		maxTimeout       time.Duration
+		failOpen         bool
+		expectedTimeout  time.Duration
+		expectedFailOpen bool
+	}{
+		{
+			name:            "TestModel",
+			registration:    model,
+			expectedTimeout: 24 * time.Second,
+		}, {
+			name:             "TestSecret",
+			registration:     secret,
+			expectedTimeout:  8 * time.Second,
+			expectedFailOpen: true,
+		}, {
+			name:            "TestMaxTimeout",
+			registration:    model,
+			maxTimeout:      5 * time.Second,
+			expectedTimeout: 5 * time.Second,
+		}, {
+			name:             "TestMaxTimeoutLongerThanWebhook",
+			registration:     secret,
+			maxTimeout:       20 * time.Second,
+			expectedTimeout:  8 * time.Second,
+			expectedFailOpen: true,
+		}, {
+			name:             "TestFailOpen",
+			registration:     model,
+			failOpen:         true,
+			expectedTimeout:  24 * time.Second,
+			expectedFailOpen: true,
+		}, {
+			name:            "TestOverrides",
+			registration:    secret,
+			overrides:       map[string]WebhookOverride{"secret": {FailurePolicy: admissionv1beta1.Fail, TimeoutSeconds: 5}},
+			expectedTimeout: 4 * time.Second,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			timeout, failOpen := validationDeadline(test.registration, test.overrides, test.maxTimeout, test.failOpen)
+			assert.Equal(t, test.expectedTimeout, timeout)
+			assert.Equal(t, test.expectedFailOpen, failOpen)
+		})
+	}
+}
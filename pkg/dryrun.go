@@ -0,0 +1,283 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	s "strings"
+
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	v8oclientset "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/typed/verrazzano/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DryRunResult is the result of the submission of a model or binding to the API server in dry-run mode
+type DryRunResult struct {
+	Path      string
+	Kind      string
+	Namespace string
+	Name      string
+	// Problems reported by the webhook, empty if the resource was accepted
+	Problems []Problem
+	// Error when the resource could not be submitted or was rejected for another reason than its validation
+	Err error
+	// Reason the resource was not submitted, empty if it was submitted
+	Skipped string
+
+	object runtime.Object
+}
+
+// DryRunManifests submits the models and bindings of the manifests to the API server with dryRun=All, so that
+// they are validated by the webhook without being stored.  Resources that already exist are submitted as
+// updates.  Models are submitted before bindings.  Models submitted in dry-run mode are not stored, so a binding
+// whose model is in the manifests but doesn't exist in the cluster would always be denied by the webhook, it is
+// skipped instead.
+func DryRunManifests(ctx context.Context, v8oClient v8oclientset.VerrazzanoV1beta1Interface, manifests []Manifest) []DryRunResult {
+	var results []DryRunResult
+	// Namespace and name of the models of the manifests
+	models := make(map[string]bool)
+	for _, manifest := range manifests {
+		if model, ok := manifest.Object.(*v1beta1v8o.VerrazzanoModel); ok {
+			result := DryRunResult{Path: manifest.Path, Kind: "VerrazzanoModel", Namespace: model.Namespace, Name: model.Name, object: model}
+			result.setError(dryRunModel(ctx, v8oClient, model), manifest.Positions)
+			results = append(results, result)
+			models[model.Namespace+"/"+model.Name] = true
+		}
+	}
+	for _, manifest := range manifests {
+		if binding, ok := manifest.Object.(*v1beta1v8o.VerrazzanoBinding); ok {
+			result := DryRunResult{Path: manifest.Path, Kind: "VerrazzanoBinding", Namespace: binding.Namespace, Name: binding.Name, object: binding}
+			if models[binding.Namespace+"/"+binding.Spec.ModelName] && !modelExists(ctx, v8oClient, binding.Namespace, binding.Spec.ModelName) {
+				result.Skipped = fmt.Sprintf("model %s is only in the manifests and doesn't exist in namespace %s, the binding can be validated once the model is created",
+					binding.Spec.ModelName, binding.Namespace)
+			} else {
+				result.setError(dryRunBinding(ctx, v8oClient, binding), manifest.Positions)
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// Check whether a model exists in the cluster, a model that can't be fetched for another reason is assumed to exist
+// so that the webhook reports the error
+func modelExists(ctx context.Context, v8oClient v8oclientset.VerrazzanoV1beta1Interface, namespace string, name string) bool {
+	_, err := v8oClient.VerrazzanoModels(namespace).Get(ctx, name, metav1.GetOptions{})
+	return !apierrors.IsNotFound(err)
+}
+
+// Set the problems of a result from the denial of the webhook, or its error for other errors
+func (r *DryRunResult) setError(err error, positions FieldPositions) {
+	if err == nil {
+		return
+	}
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || !s.Contains(status.Status().Message, deniedRequestMarker) {
+		r.Err = err
+		return
+	}
+	r.Problems = ProblemsFromStatus(status.Status())
+	for i, problem := range r.Problems {
+		if position, ok := positions.find(problem.Field); ok {
+			r.Problems[i].Line, r.Problems[i].Column = position.Line, position.Column
+		}
+	}
+}
+
+// Submit a model in dry-run mode, as an update if it already exists
+func dryRunModel(ctx context.Context, v8oClient v8oclientset.VerrazzanoV1beta1Interface, model *v1beta1v8o.VerrazzanoModel) error {
+	models := v8oClient.VerrazzanoModels(model.Namespace)
+	_, err := models.Create(ctx, model, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := models.Get(ctx, model.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	updated := model.DeepCopy()
+	updated.ResourceVersion = existing.ResourceVersion
+	_, err = models.Update(ctx, updated, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	return err
+}
+
+// Submit a binding in dry-run mode, as an update if it already exists
+func dryRunBinding(ctx context.Context, v8oClient v8oclientset.VerrazzanoV1beta1Interface, binding *v1beta1v8o.VerrazzanoBinding) error {
+	bindings := v8oClient.VerrazzanoBindings(binding.Namespace)
+	_, err := bindings.Create(ctx, binding, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := bindings.Get(ctx, binding.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	updated := binding.DeepCopy()
+	updated.ResourceVersion = existing.ResourceVersion
+	_, err = bindings.Update(ctx, updated, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	return err
+}
+
+// ProblemGroup contains the problems of one component of a model or binding
+type ProblemGroup struct {
+	// Name of the component, empty for the problems that don't belong to a component
+	Component string
+	// Path of the component, like spec.helidonApplications[1]
+	Field    string
+	Problems []Problem
+}
+
+// Groups returns the problems of the result grouped by component, in the order of their first problem
+func (r DryRunResult) Groups() []ProblemGroup {
+	content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(r.object)
+	var groups []ProblemGroup
+	index := make(map[string]int)
+	for _, problem := range r.Problems {
+		field, name := getComponentField(content, problem.Field)
+		i, ok := index[field]
+		if !ok {
+			i = len(groups)
+			index[field] = i
+			groups = append(groups, ProblemGroup{Component: name, Field: field})
+		}
+		groups[i].Problems = append(groups[i].Problems, problem)
+	}
+	return groups
+}
+
+// Matches a segment of a field path, either a field name or a list index
+var fieldSegmentPattern = regexp.MustCompile(`([a-zA-Z0-9_]+)|\[([0-9]+)\]`)
+
+// Get the path and name of the component a field belongs to, which is the first list item with a name under
+// the spec of the resource.  Returns empty strings if the field doesn't belong to a component.
+func getComponentField(content map[string]interface{}, field string) (string, string) {
+	if !s.HasPrefix(field, "spec.") {
+		return "", ""
+	}
+	var value interface{} = content
+	path := ""
+	for _, segment := range fieldSegmentPattern.FindAllStringSubmatch(field, -1) {
+		if segment[1] != "" {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return "", ""
+			}
+			value = object[segment[1]]
+			path = s.TrimPrefix(path+"."+segment[1], ".")
+			continue
+		}
+		list, ok := value.([]interface{})
+		i, _ := strconv.Atoi(segment[2])
+		if !ok || i >= len(list) {
+			return "", ""
+		}
+		value = list[i]
+		path += segment[0]
+		if item, ok := value.(map[string]interface{}); ok {
+			if name, ok := item["name"].(string); ok && name != "" {
+				return path, name
+			}
+		}
+	}
+	return "", ""
+}
+
+// Suggestions for the problems of a rule, checked before the suggestions for problem types.  The suggestion of an
+// entry without a problem type is for all the problems of the rule.
+var ruleSuggestions = []struct {
+	rule        string
+	problemType string
+	suggestion  string
+}{
+	{ruleSecretReferences, problemTypeNotFound, "create the secret with kubectl create secret in the namespace given in the message, then submit again"},
+	{ruleSecretReferences, problemTypeInvalid, "recreate the secret with kubectl create secret docker-registry"},
+	{ruleConfigMapReferences, problemTypeNotFound, "create the config map with kubectl create configmap in the namespace given in the message, or add the missing key with kubectl edit configmap"},
+	{ruleDatabaseBindings, problemTypeForbidden, "use a database host allowed by the validation policy, or ask the cluster administrator to allow it"},
+	{ruleGeneratedHostnames, "", "shorten the binding name, or the ingress DNS name, so that the generated hostnames fit"},
+	{ruleHelidonPlacementPorts, "", "change one of the ports so that they are different"},
+}
+
+// Suggestions for problem types
+var typeSuggestions = map[string]string{
+	"Invalid value":     "fix the value so that it matches the expected format",
+	"Duplicate value":   "give each item a unique value",
+	"Required value":    "set the field",
+	"Unsupported value": "use one of the supported values listed in the message",
+	"Forbidden":         "the validation policy doesn't allow this value, ask the cluster administrator about the policy",
+	"Not found":         "create the missing resource first, or fix its name",
+}
+
+// Suggestion returns a suggestion for fixing a problem from its rule and type, or an empty string if there is none.
+// The problems read from the status of a denied request don't have a rule and only get the suggestion of their type.
+func Suggestion(problem Problem) string {
+	if problem.Rule != "" {
+		for _, suggestion := range ruleSuggestions {
+			if suggestion.rule == problem.Rule && (suggestion.problemType == "" || suggestion.problemType == problem.Type) {
+				return suggestion.suggestion
+			}
+		}
+	}
+	return typeSuggestions[problem.Type]
+}
+
+// ANSI escape sequences used when writing a report in color
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+)
+
+// WriteDryRunReport writes the results of dry runs with the problems grouped by component and a suggestion for
+// each problem, using ANSI colors if color is set
+func WriteDryRunReport(w io.Writer, results []DryRunResult, color bool) error {
+	paint := func(code string, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+
+	var lines []string
+	for _, result := range results {
+		resource := fmt.Sprintf("%s %s/%s (%s)", result.Kind, result.Namespace, result.Name, result.Path)
+		switch {
+		case result.Err != nil:
+			lines = append(lines, fmt.Sprintf("%s %s: %v", paint(colorRed+colorBold, "ERROR"), resource, result.Err))
+		case result.Skipped != "":
+			lines = append(lines, fmt.Sprintf("%s %s: %s", paint(colorYellow+colorBold, "SKIPPED"), resource, result.Skipped))
+		case len(result.Problems) == 0:
+			lines = append(lines, fmt.Sprintf("%s %s", paint(colorGreen+colorBold, "VALID"), resource))
+		default:
+			lines = append(lines, fmt.Sprintf("%s %s: %d problem(s)", paint(colorRed+colorBold, "INVALID"), resource, len(result.Problems)))
+			for _, group := range result.Groups() {
+				heading := result.Kind
+				if group.Component != "" {
+					heading = fmt.Sprintf("%s (%s)", group.Component, group.Field)
+				}
+				lines = append(lines, "  "+paint(colorBold, heading))
+				for _, problem := range group.Problems {
+					lines = append(lines, fmt.Sprintf("    %s %s", paint(colorCyan, problem.location(result.Path)+":"), paint(colorRed, problem.Message)))
+					if suggestion := Suggestion(problem); suggestion != "" {
+						lines = append(lines, "      "+paint(colorYellow, "suggestion: "+suggestion))
+					}
+				}
+			}
+		}
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
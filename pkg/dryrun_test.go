@@ -0,0 +1,192 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	v8ofake "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// Problems of a denied binding used by the dry-run tests
+var dryRunProblems = []Problem{
+	fieldProblem("spec.helidonBindings[1].replicas", problemTypeInvalid, "12: must be less than or equal to 10"),
+	fieldProblem("spec.placement[0].namespaces[0].name", problemTypeInvalid, "\"bad_name\": not valid"),
+	newProblem("binding references cluster(s) \"local\" that do not exist in namespace default"),
+}
+
+// Create a binding with placements and Helidon bindings
+func newDryRunBinding(name string) *v1beta1v8o.VerrazzanoBinding {
+	return &v1beta1v8o.VerrazzanoBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1beta1v8o.VerrazzanoBindingSpec{
+			ModelName: "model",
+			HelidonBindings: []v1beta1v8o.VerrazzanoHelidonBinding{
+				{Name: "hello-1"},
+				{Name: "hello-2"},
+			},
+			Placement: []v1beta1v8o.VerrazzanoPlacement{
+				{Name: "local", Namespaces: []v1beta1v8o.KubernetesNamespace{{Name: "bad_name"}}},
+			},
+		},
+	}
+}
+
+// TestDryRunManifests tests the submission of models and bindings in dry-run mode
+// GIVEN manifests with a model that already exists, a new model, a binding denied by the webhook, a binding that can't be submitted and a binding of the new model
+//  WHEN DryRunManifests is called
+//  THEN the model should be submitted as an update, the problems of the denied binding should be returned with
+//	 their positions, the error of the other binding should be returned and the binding of the new model should
+//	 be skipped
+func TestDryRunManifests(t *testing.T) {
+	model := &v1beta1v8o.VerrazzanoModel{ObjectMeta: metav1.ObjectMeta{Name: "model", Namespace: "default", ResourceVersion: "3"}}
+	clientset := v8ofake.NewSimpleClientset(model)
+	newBinding := newDryRunBinding("new")
+	newBinding.Spec.ModelName = "new-model"
+	// The new model is not stored, like a model submitted in dry-run mode
+	clientset.PrependReactor("create", "verrazzanomodels", func(action ktesting.Action) (bool, runtime.Object, error) {
+		created := action.(ktesting.CreateAction).GetObject()
+		return created.(*v1beta1v8o.VerrazzanoModel).Name == "new-model", created, nil
+	})
+	clientset.PrependReactor("create", "verrazzanobindings", func(action ktesting.Action) (bool, runtime.Object, error) {
+		binding := action.(ktesting.CreateAction).GetObject().(*v1beta1v8o.VerrazzanoBinding)
+		if binding.Name == "denied" {
+			status := *errorAdmissionReview(dryRunProblems).Response.Result
+			status.Status = metav1.StatusFailure
+			status.Code = 400
+			status.Message = "admission webhook \"verrazzano-validation.verrazzano.io\" denied the request: " + status.Message
+			return true, nil, &apierrors.StatusError{ErrStatus: status}
+		}
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "verrazzano.io", Resource: "verrazzanobindings"}, binding.Name, nil)
+	})
+
+	manifests := []Manifest{
+		{Path: "binding.yaml", Object: newDryRunBinding("denied"), Positions: FieldPositions{"spec.helidonBindings[1]": {Line: 9, Column: 5}}},
+		{Path: "binding.yaml", Object: newDryRunBinding("forbidden")},
+		{Path: "model.yaml", Object: model.DeepCopy()},
+		{Path: "new.yaml", Object: &v1beta1v8o.VerrazzanoModel{ObjectMeta: metav1.ObjectMeta{Name: "new-model", Namespace: "default"}}},
+		{Path: "new.yaml", Object: newBinding},
+	}
+	results := DryRunManifests(context.TODO(), clientset.VerrazzanoV1beta1(), manifests)
+	assert.Len(t, results, 5)
+
+	assert.Equal(t, "VerrazzanoModel", results[0].Kind)
+	assert.Nil(t, results[0].Err)
+	assert.Empty(t, results[0].Problems)
+	var updated bool
+	for _, action := range clientset.Actions() {
+		if update, ok := action.(ktesting.UpdateActionImpl); ok && update.GetResource().Resource == "verrazzanomodels" {
+			updated = true
+		}
+	}
+	assert.True(t, updated, "the existing model should be submitted as an update")
+
+	assert.Equal(t, "new-model", results[1].Name)
+	assert.Nil(t, results[1].Err)
+
+	assert.Equal(t, "denied", results[2].Name)
+	assert.Nil(t, results[2].Err)
+	assert.Len(t, results[2].Problems, 3)
+	assert.Equal(t, "spec.helidonBindings[1].replicas", results[2].Problems[0].Field)
+	assert.Equal(t, "Invalid value", results[2].Problems[0].Type)
+	assert.Equal(t, 9, results[2].Problems[0].Line)
+	assert.Equal(t, 0, results[2].Problems[1].Line)
+
+	assert.Equal(t, "forbidden", results[3].Name)
+	assert.True(t, apierrors.IsForbidden(results[3].Err))
+	assert.Empty(t, results[3].Problems)
+
+	assert.Equal(t, "new", results[4].Name)
+	assert.Nil(t, results[4].Err)
+	assert.Empty(t, results[4].Problems)
+	assert.Equal(t, "model new-model is only in the manifests and doesn't exist in namespace default, the binding can be validated once the model is created", results[4].Skipped)
+	for _, action := range clientset.Actions() {
+		if create, ok := action.(ktesting.CreateActionImpl); ok && create.GetResource().Resource == "verrazzanobindings" {
+			assert.NotEqual(t, "new", create.GetObject().(*v1beta1v8o.VerrazzanoBinding).Name, "the binding of the new model should not be submitted")
+		}
+	}
+}
+
+// TestDryRunResultGroups tests grouping of problems by component
+// GIVEN the problems of a binding
+//  WHEN Groups is called
+//  THEN the problems should be grouped by the named list item under the spec they belong to
+func TestDryRunResultGroups(t *testing.T) {
+	result := DryRunResult{object: newDryRunBinding("binding"), Problems: append(dryRunProblems,
+		fieldProblem("spec.helidonBindings[1].name", problemTypeInvalid, "\"hello-2\": not valid"))}
+	groups := result.Groups()
+	assert.Len(t, groups, 3)
+	assert.Equal(t, "hello-2", groups[0].Component)
+	assert.Equal(t, "spec.helidonBindings[1]", groups[0].Field)
+	assert.Len(t, groups[0].Problems, 2)
+	assert.Equal(t, "local", groups[1].Component)
+	assert.Equal(t, "spec.placement[0]", groups[1].Field)
+	assert.Equal(t, "", groups[2].Component)
+	assert.Equal(t, "binding references cluster(s) \"local\" that do not exist in namespace default", groups[2].Problems[0].Message)
+}
+
+// TestSuggestion tests suggestions for problems
+// GIVEN problems
+//  WHEN Suggestion is called
+//  THEN the suggestion for the rule and type of the problem, or else for its type, should be returned
+func TestSuggestion(t *testing.T) {
+	tests := []struct {
+		problem  Problem
+		expected string
+	}{
+		{Problem{Rule: ruleSecretReferences, Type: problemTypeNotFound, Message: "model references secret \"docker-secret\" for component c.  This secret must be created in the default namespace before proceeding."},
+			"create the secret with kubectl create secret in the namespace given in the message, then submit again"},
+		{Problem{Rule: ruleSecretReferences, Type: problemTypeInvalid, Message: "Image pull secrets must have type"},
+			"recreate the secret with kubectl create secret docker-registry"},
+		{Problem{Rule: ruleGeneratedHostnames, Type: problemTypeInvalid, Message: "the VMI hostname is greater than 64 characters"},
+			"shorten the binding name, or the ingress DNS name, so that the generated hostnames fit"},
+		{Problem{Rule: ruleDatabaseBindings, Type: problemTypeForbidden, Message: "spec.databaseBindings[0]: Forbidden: database host h of database binding b is not allowed by policy, x"},
+			"use a database host allowed by the validation policy, or ask the cluster administrator to allow it"},
+		{Problem{Type: problemTypeForbidden, Message: "spec.databaseBindings[0]: Forbidden: database host h of database binding b is not allowed by policy, x"},
+			"the validation policy doesn't allow this value, ask the cluster administrator about the policy"},
+		{Problem{Rule: ruleBindingComponents, Type: problemTypeDuplicate, Message: "spec.coherenceClusters[1].name: Duplicate value: \"c\""}, "give each item a unique value"},
+		{Problem{Message: "The secret docker-secret must be created"}, ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Suggestion(test.problem))
+	}
+}
+
+// TestWriteDryRunReport tests writing of dry-run results
+// GIVEN a valid, an invalid, a failed and a skipped result
+//  WHEN WriteDryRunReport is called with and without color
+//  THEN the problems should be written grouped by component with suggestions, using colors only if asked to
+func TestWriteDryRunReport(t *testing.T) {
+	results := []DryRunResult{
+		{Path: "model.yaml", Kind: "VerrazzanoModel", Namespace: "default", Name: "model"},
+		{Path: "binding.yaml", Kind: "VerrazzanoBinding", Namespace: "default", Name: "binding", object: newDryRunBinding("binding"),
+			Problems: []Problem{{Field: "spec.helidonBindings[1].replicas", Type: "Invalid value", Message: "spec.helidonBindings[1].replicas: Invalid value: 12: must be less than or equal to 10", Line: 9, Column: 5}}},
+		{Path: "other.yaml", Kind: "VerrazzanoBinding", Namespace: "default", Name: "other", Err: apierrors.NewForbidden(schema.GroupResource{Resource: "verrazzanobindings"}, "other", nil)},
+		{Path: "new.yaml", Kind: "VerrazzanoBinding", Namespace: "default", Name: "new", Skipped: "model new-model is only in the manifests"},
+	}
+
+	var out bytes.Buffer
+	assert.Nil(t, WriteDryRunReport(&out, results, false))
+	assert.Equal(t, "VALID VerrazzanoModel default/model (model.yaml)\n"+
+		"INVALID VerrazzanoBinding default/binding (binding.yaml): 1 problem(s)\n"+
+		"  hello-2 (spec.helidonBindings[1])\n"+
+		"    binding.yaml:9:5: spec.helidonBindings[1].replicas: Invalid value: 12: must be less than or equal to 10\n"+
+		"      suggestion: fix the value so that it matches the expected format\n"+
+		"ERROR VerrazzanoBinding default/other (other.yaml): verrazzanobindings \"other\" is forbidden: <nil>\n"+
+		"SKIPPED VerrazzanoBinding default/new (new.yaml): model new-model is only in the manifests\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, WriteDryRunReport(&out, results, true))
+	assert.Contains(t, out.String(), colorGreen+colorBold+"VALID"+colorReset)
+	assert.Contains(t, out.String(), colorYellow+"suggestion: ")
+}
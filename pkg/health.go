@@ -0,0 +1,128 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// HealthCheck returns an error when a dependency of the admission controller is not healthy
+type HealthCheck func() error
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// HealthChecker serves the liveness and readiness of the admission controller, computed from named checks
+type HealthChecker struct {
+	mutex           sync.RWMutex
+	livenessChecks  []namedHealthCheck
+	readinessChecks []namedHealthCheck
+}
+
+// AddLivenessCheck adds a check that restarts the admission controller when it fails.  Liveness checks are
+// also readiness checks.
+func (h *HealthChecker) AddLivenessCheck(name string, check HealthCheck) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.livenessChecks = append(h.livenessChecks, namedHealthCheck{name: name, check: check})
+}
+
+// AddReadinessCheck adds a check that stops requests from being sent to the admission controller when it fails
+func (h *HealthChecker) AddReadinessCheck(name string, check HealthCheck) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.readinessChecks = append(h.readinessChecks, namedHealthCheck{name: name, check: check})
+}
+
+// ServeHealthz responds with the result of the liveness checks
+func (h *HealthChecker) ServeHealthz(w http.ResponseWriter, r *http.Request) {
+	h.mutex.RLock()
+	checks := append([]namedHealthCheck{}, h.livenessChecks...)
+	h.mutex.RUnlock()
+	serveHealthChecks(w, "healthz", checks)
+}
+
+// ServeReadyz responds with the result of the liveness and readiness checks
+func (h *HealthChecker) ServeReadyz(w http.ResponseWriter, r *http.Request) {
+	h.mutex.RLock()
+	checks := append(append([]namedHealthCheck{}, h.livenessChecks...), h.readinessChecks...)
+	h.mutex.RUnlock()
+	serveHealthChecks(w, "readyz", checks)
+}
+
+// Run the checks and respond with a line for each check, with status 500 if a check failed
+func serveHealthChecks(w http.ResponseWriter, endpoint string, checks []namedHealthCheck) {
+	body := ""
+	failed := false
+	for _, check := range checks {
+		if err := check.check(); err != nil {
+			zap.S().Warnf("%s check %s failed: %v", endpoint, check.name, err)
+			body += fmt.Sprintf("[-]%s failed: %v\n", check.name, err)
+			failed = true
+		} else {
+			body += fmt.Sprintf("[+]%s ok\n", check.name)
+		}
+	}
+	status := http.StatusOK
+	if failed {
+		status = http.StatusInternalServerError
+		body += endpoint + " check failed\n"
+	} else {
+		body += endpoint + " check passed\n"
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if _, err := w.Write([]byte(body)); err != nil {
+		zap.S().Errorf("error with write of %s response: %v", endpoint, err)
+	}
+}
+
+// CertificateCheck fails when the serving certificate is not loaded, not yet valid or expired
+func CertificateCheck(getCertificate func() (*tls.Certificate, error)) HealthCheck {
+	return func() error {
+		certificate, err := getCertificate()
+		if err != nil {
+			return err
+		}
+		if certificate == nil || len(certificate.Certificate) == 0 {
+			return errors.New("no certificate loaded")
+		}
+		leaf := certificate.Leaf
+		if leaf == nil {
+			if leaf, err = x509.ParseCertificate(certificate.Certificate[0]); err != nil {
+				return fmt.Errorf("failed to parse certificate: %v", err)
+			}
+		}
+		now := time.Now()
+		if now.Before(leaf.NotBefore) {
+			return fmt.Errorf("certificate is not valid before %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+		}
+		if now.After(leaf.NotAfter) {
+			return fmt.Errorf("certificate expired at %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// APIServerCheck fails when the Kubernetes API server can't be reached
+func APIServerCheck(k8sClient kubernetes.Interface) HealthCheck {
+	return func() error {
+		if _, err := k8sClient.Discovery().ServerVersion(); err != nil {
+			return fmt.Errorf("failed to reach the API server: %v", err)
+		}
+		return nil
+	}
+}
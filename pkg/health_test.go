@@ -0,0 +1,110 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Create a self-signed certificate valid between the given times
+func newTestCertificate(t *testing.T, notBefore time.Time, notAfter time.Time) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "verrazzano-validation.verrazzano-system.svc"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// TestCertificateCheck tests checking of the serving certificate
+// GIVEN certificates that are valid, expired, not yet valid or not loaded
+//  WHEN the certificate check is run
+//  THEN the check should only pass for the valid certificate
+func TestCertificateCheck(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		certificate *tls.Certificate
+		err         error
+		expected    string
+	}{
+		{name: "valid", certificate: newTestCertificate(t, now.Add(-time.Hour), now.Add(time.Hour))},
+		{name: "expired", certificate: newTestCertificate(t, now.Add(-2*time.Hour), now.Add(-time.Hour)), expected: "certificate expired at"},
+		{name: "not yet valid", certificate: newTestCertificate(t, now.Add(time.Hour), now.Add(2*time.Hour)), expected: "certificate is not valid before"},
+		{name: "not loaded", expected: "no certificate loaded"},
+		{name: "error", err: errors.New("failed to read cert.pem"), expected: "failed to read cert.pem"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CertificateCheck(func() (*tls.Certificate, error) { return test.certificate, test.err })()
+			if test.expected == "" {
+				assert.Nil(t, err)
+			} else if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), test.expected)
+			}
+		})
+	}
+}
+
+// TestAPIServerCheck tests checking of the connectivity to the API server
+// GIVEN a clientset that can reach the API server
+//  WHEN the API server check is run
+//  THEN the check should pass
+func TestAPIServerCheck(t *testing.T) {
+	assert.Nil(t, APIServerCheck(k8sfake.NewSimpleClientset())())
+}
+
+// TestHealthChecker tests serving of the liveness and readiness
+// GIVEN a health checker with a passing liveness check and a readiness check
+//  WHEN the liveness and readiness are requested while the readiness check fails and after it passes
+//  THEN the liveness should pass and the readiness should only pass when the readiness check passes
+func TestHealthChecker(t *testing.T) {
+	synced := false
+	health := &HealthChecker{}
+	health.AddLivenessCheck("certificate", func() error { return nil })
+	health.AddReadinessCheck("verrazzano-config", func() error {
+		if !synced {
+			return errors.New("not synced")
+		}
+		return nil
+	})
+
+	serve := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder
+	}
+
+	recorder := serve(health.ServeHealthz)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "[+]certificate ok\nhealthz check passed\n", recorder.Body.String())
+
+	recorder = serve(health.ServeReadyz)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "[+]certificate ok\n[-]verrazzano-config failed: not synced\nreadyz check failed\n", recorder.Body.String())
+
+	synced = true
+	recorder = serve(health.ServeReadyz)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "[+]certificate ok\n[+]verrazzano-config ok\nreadyz check passed\n", recorder.Body.String())
+}
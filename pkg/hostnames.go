@@ -0,0 +1,98 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"fmt"
+	s "strings"
+
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	"go.uber.org/zap"
+)
+
+// Maximum length of a hostname, the length of the common name of the certificates created for the hostnames
+const maxHostnameLen = 64
+
+// Endpoints of the Verrazzano Monitoring Instance created for a binding
+var vmiEndpoints = []string{"grafana", "prometheus", "kibana", "elasticsearch"}
+
+// generatedHostname is a hostname created for a binding
+type generatedHostname struct {
+	// Field of the binding the hostname is derived from
+	field string
+	// Description of what the hostname is used for
+	usage string
+	host  string
+}
+
+// Get the hostnames created for a binding.  Parts of the hostnames that come from the Verrazzano URI are
+// left out when the URI is not known.
+func getGeneratedHostnames(binding v1beta1v8o.VerrazzanoBinding, verrazzanoURI string) []generatedHostname {
+	vmiDomain := joinHostname("vmi", binding.Name, verrazzanoURI)
+	hostnames := []generatedHostname{{field: "metadata.name", usage: "VMI domain name", host: "*." + vmiDomain}}
+	for _, endpoint := range vmiEndpoints {
+		hostnames = append(hostnames, generatedHostname{field: "metadata.name", usage: "VMI " + endpoint + " hostname", host: joinHostname(endpoint, vmiDomain)})
+	}
+	for i, ingressBinding := range binding.Spec.IngressBindings {
+		dnsName := s.TrimSpace(ingressBinding.DnsName)
+		if dnsName != "*" && dnsName != "" {
+			hostnames = append(hostnames, generatedHostname{field: fmt.Sprintf("spec.ingressBindings[%d].dnsName", i), usage: "hostname of ingress binding " + ingressBinding.Name, host: dnsName})
+		}
+	}
+	return hostnames
+}
+
+// Join the non-empty labels of a hostname
+func joinHostname(labels ...string) string {
+	var parts []string
+	for _, label := range labels {
+		if label != "" {
+			parts = append(parts, label)
+		}
+	}
+	return s.Join(parts, ".")
+}
+
+// Validate the length of the hostnames created for a binding.  Each hostname must not be longer than 64
+// characters, the VMI hostnames are reported once as they all depend on the binding name.  When the Verrazzano
+// URI is not known the hostnames can't be validated, which is a problem of its own.
+func validateGeneratedHostnames(binding v1beta1v8o.VerrazzanoBinding, verrazzanoURI string) []Problem {
+	zap.S().Debugw("In validateGeneratedHostnames code")
+
+	var errMessages []Problem
+	if verrazzanoURI == "" {
+		errMessages = append(errMessages, newProblem("the Verrazzano URI is not known, the hostnames of binding %s don't include it and can't be validated", binding.Name))
+	}
+
+	hostnames := getGeneratedHostnames(binding, verrazzanoURI)
+	// The VMI hostname with the largest excess tells how much shorter the binding name must be
+	var longestVMI generatedHostname
+	vmiReported := false
+	for _, hostname := range hostnames {
+		if hostname.field == "metadata.name" && len(hostname.host) > len(longestVMI.host) {
+			longestVMI = hostname
+		}
+	}
+	for _, hostname := range hostnames {
+		excess := len(hostname.host) - maxHostnameLen
+		if excess <= 0 {
+			continue
+		}
+		if hostname.field == "metadata.name" {
+			if vmiReported {
+				continue
+			}
+			errMessages = append(errMessages, newFieldProblem("metadata.name", problemTypeInvalid, "the %s is greater than %d characters: %s.  The binding name %s is %d characters long.  Reduce the size by using a binding name that is at least %d characters shorter.", longestVMI.usage, maxHostnameLen, longestVMI.host, binding.Name, len(binding.Name), len(longestVMI.host)-maxHostnameLen))
+			vmiReported = true
+		} else {
+			errMessages = append(errMessages, fieldProblem(hostname.field, problemTypeInvalid,
+				fmt.Sprintf("\"%s\": the %s is greater than %d characters, reduce its size by at least %d characters", hostname.host, hostname.usage, maxHostnameLen, excess)))
+		}
+	}
+
+	if len(errMessages) > 0 {
+		zap.S().Errorw(problemsMessage(errMessages))
+	}
+	return errMessages
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzv1b "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+)
+
+// TestGetGeneratedHostnames tests the hostnames created for a binding
+// GIVEN a VerrazzanoBinding and a Verrazzano URI
+//  WHEN getGeneratedHostnames is called
+//  THEN the VMI hostnames and the DNS names of the ingress bindings should be returned
+func TestGetGeneratedHostnames(t *testing.T) {
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	binding.Spec.IngressBindings[1].DnsName = "books.example.com"
+
+	var hosts []string
+	for _, hostname := range getGeneratedHostnames(*binding, "v8o.example.com") {
+		hosts = append(hosts, hostname.host)
+	}
+	assert.Equal(t, []string{"*.vmi.bobs-books-binding.v8o.example.com", "grafana.vmi.bobs-books-binding.v8o.example.com",
+		"prometheus.vmi.bobs-books-binding.v8o.example.com", "kibana.vmi.bobs-books-binding.v8o.example.com",
+		"elasticsearch.vmi.bobs-books-binding.v8o.example.com", "books.example.com"}, hosts)
+
+	assert.Equal(t, "*.vmi.bobs-books-binding", getGeneratedHostnames(*binding, "")[0].host)
+}
+
+// TestValidateGeneratedHostnames tests validation of the length of the hostnames created for a binding
+// GIVEN a VerrazzanoBinding and a Verrazzano URI
+//  WHEN validateGeneratedHostnames is called
+//  THEN the validation should produce messages containing the expected substrings
+func TestValidateGeneratedHostnames(t *testing.T) {
+	longIngress := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	longIngress.Spec.IngressBindings[1].DnsName = "bobs-books-bookstore-front-end-application.apps.verrazzano.example.com"
+	tests := []struct {
+		name                    string
+		binding                 *vzv1b.VerrazzanoBinding
+		verrazzanoURI           string
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:          "TestValidHostnames",
+			binding:       ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			verrazzanoURI: "v8o.example.com",
+		}, {
+			name:                    "TestLongVerrazzanoURI",
+			binding:                 ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			verrazzanoURI:           "my-verrazzano-environment.verrazzano.example.com",
+			expectedErrorSubstrings: []string{"the VMI elasticsearch hostname is greater than 64 characters: elasticsearch.vmi.bobs-books-binding.my-verrazzano-environment.verrazzano.example.com.  The binding name bobs-books-binding is 18 characters long.  Reduce the size by using a binding name that is at least 21 characters shorter."},
+		}, {
+			name:                    "TestLongEndpointHostname",
+			binding:                 ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			verrazzanoURI:           "my-verrazzano.verrazzano.example.com",
+			expectedErrorSubstrings: []string{"the VMI elasticsearch hostname is greater than 64 characters: elasticsearch.vmi.bobs-books-binding.my-verrazzano.verrazzano.example.com.  The binding name bobs-books-binding is 18 characters long.  Reduce the size by using a binding name that is at least 9 characters shorter."},
+		}, {
+			name:                    "TestLongIngressHostname",
+			binding:                 longIngress,
+			verrazzanoURI:           "v8o.example.com",
+			expectedErrorSubstrings: []string{"spec.ingressBindings[1].dnsName: Invalid value: \"bobs-books-bookstore-front-end-application.apps.verrazzano.example.com\": the hostname of ingress binding bobs-ingress is greater than 64 characters, reduce its size by at least 6 characters"},
+		}, {
+			name:                    "TestUnknownVerrazzanoURI",
+			binding:                 ReadBinding("testdata/bobs-books-v2-binding.yaml"),
+			expectedErrorSubstrings: []string{"the Verrazzano URI is not known, the hostnames of binding bobs-books-binding don't include it and can't be validated"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errMessages := validateGeneratedHostnames(*test.binding, test.verrazzanoURI)
+			assert.Equal(t, len(test.expectedErrorSubstrings), len(errMessages))
+			errorMessage := problemsMessage(errMessages)
+			for _, s := range test.expectedErrorSubstrings {
+				if !strings.Contains(errorMessage, s) {
+					t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+				}
+			}
+		})
+	}
+}
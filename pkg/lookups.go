@@ -0,0 +1,44 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"sync"
+)
+
+// Maximum number of API lookups of a validation run concurrently
+const maxConcurrentLookups = 4
+
+// Run a lookup for each index from 0 to count-1, with at most maxConcurrentLookups lookups running at a time.
+// Returns when all the lookups have completed.  Lookups store their results at their index so that the results
+// are processed in a deterministic order.
+func forEachConcurrently(count int, lookup func(index int)) {
+	semaphore := make(chan struct{}, maxConcurrentLookups)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(index int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			lookup(index)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Get the distinct values of a list in the order they first appear, and the index of each value in the result
+func distinctValues(values []string) ([]string, map[string]int) {
+	var distinct []string
+	indexes := make(map[string]int)
+	for _, value := range values {
+		if _, ok := indexes[value]; !ok {
+			indexes[value] = len(distinct)
+			distinct = append(distinct, value)
+		}
+	}
+	return distinct, indexes
+}
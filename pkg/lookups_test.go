@@ -0,0 +1,139 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzv1b "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	kv1b "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// TestForEachConcurrently tests the bounded parallelism of the lookups
+// GIVEN more lookups than maxConcurrentLookups
+//  WHEN forEachConcurrently is called
+//  THEN each lookup should run once, lookups should run concurrently and at most maxConcurrentLookups at a time
+func TestForEachConcurrently(t *testing.T) {
+	const count = 20
+	var running, maxRunning int32
+	var mutex sync.Mutex
+	calls := make([]int, count)
+	forEachConcurrently(count, func(index int) {
+		current := atomic.AddInt32(&running, 1)
+		mutex.Lock()
+		calls[index]++
+		if current > maxRunning {
+			maxRunning = current
+		}
+		mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	})
+	for i, c := range calls {
+		assert.Equal(t, 1, c, "lookup %d", i)
+	}
+	assert.True(t, maxRunning > 1, "lookups should run concurrently")
+	assert.True(t, maxRunning <= maxConcurrentLookups, "at most %d lookups should run at a time, got %d", maxConcurrentLookups, maxRunning)
+
+	forEachConcurrently(0, func(index int) {
+		t.Errorf("no lookup should run, got %d", index)
+	})
+}
+
+// TestDistinctValues tests deduplication of the lookups
+// GIVEN a list of values with duplicates
+//  WHEN distinctValues is called
+//  THEN the distinct values should be returned in the order they first appear with their indexes
+func TestDistinctValues(t *testing.T) {
+	distinct, indexes := distinctValues([]string{"ocr", "mysql", "ocr", "weblogic", "mysql"})
+	assert.Equal(t, []string{"ocr", "mysql", "weblogic"}, distinct)
+	assert.Equal(t, map[string]int{"ocr": 0, "mysql": 1, "weblogic": 2}, indexes)
+
+	distinct, indexes = distinctValues(nil)
+	assert.Empty(t, distinct)
+	assert.Empty(t, indexes)
+}
+
+// TestValidateModelSecretsFetchedOnce tests deduplication of the secrets of a model
+// GIVEN a model referencing the ocr secret from several components and no secrets
+//  WHEN validateModelSecrets is called
+//  THEN each secret should be fetched once and the first reference of the model should be reported
+func TestValidateModelSecretsFetchedOnce(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	references := modelSecretReferences(*model)
+	ocrReferences := 0
+	var names []string
+	for _, reference := range references {
+		if reference.name == "ocr" {
+			ocrReferences++
+		}
+		names = append(names, reference.name)
+	}
+	distinct, _ := distinctValues(names)
+	assert.True(t, ocrReferences > 1, "the model should reference ocr several times")
+
+	_, err := fetchSecret(context.TODO(), &Clientsets{K8sClient: k8sfake.NewSimpleClientset()}, references[0].name, references[0].component)
+	expected := checkSecretReference(references[0], nil, err)
+	for i := 0; i < 10; i++ {
+		k8sClient := k8sfake.NewSimpleClientset()
+		problems := validateModelSecrets(context.TODO(), *model, &Clientsets{K8sClient: k8sClient})
+		assert.Equal(t, expected, problems)
+		assert.Equal(t, len(distinct), countActions(k8sClient, "get", "secrets"))
+	}
+}
+
+// TestValidateModelConfigMapsFetchedOnce tests deduplication of the config maps of a model
+// GIVEN a model referencing the mysql-initdb-config config map from a volume and a container and no config maps
+//  WHEN validateModelConfigMaps is called
+//  THEN each config map should be fetched once and the first reference of the model should be reported
+func TestValidateModelConfigMapsFetchedOnce(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	model.Spec.GenericComponents[0].Deployment.Containers[0].EnvFrom = []corev1.EnvFromSource{
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "mysql-config"}}},
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "mysql-initdb-config"}}}}
+	references := modelConfigMapReferences(*model)
+	assert.Len(t, references, 3)
+
+	for i := 0; i < 10; i++ {
+		k8sClient := k8sfake.NewSimpleClientset()
+		message := problemsMessage(validateModelConfigMaps(context.TODO(), *model, &Clientsets{K8sClient: k8sClient}))
+		assert.Equal(t, "model references genericComponents.Deployment.Containers.EnvFrom \"mysql-config\" for component mysql.  This config map must be created in the default namespace before proceeding.", message)
+		assert.Equal(t, 2, countActions(k8sClient, "get", "configmaps"))
+	}
+}
+
+// TestValidateClustersFetchedOnce tests deduplication of the clusters of a binding
+// GIVEN a binding with several placements on missing clusters, one of them twice
+//  WHEN validateClusters is called
+//  THEN each cluster should be fetched once and the missing clusters reported in the order of the placements
+func TestValidateClustersFetchedOnce(t *testing.T) {
+	binding := vzv1b.VerrazzanoBinding{}
+	for _, name := range []string{"east", "west", "east", "north", "south", "central"} {
+		binding.Spec.Placement = append(binding.Spec.Placement, vzv1b.VerrazzanoPlacement{Name: name})
+	}
+	cluster := &vzv1b.VerrazzanoManagedCluster{}
+	cluster.Namespace = "default"
+	cluster.Name = "north"
+	review := kv1b.AdmissionReview{Request: &kv1b.AdmissionRequest{Namespace: "default"}}
+
+	for i := 0; i < 10; i++ {
+		v8oClient := NewFakeVzClient(cluster)
+		message := problemsMessage(validateClusters(context.TODO(), review, binding, &Clientsets{V8oClient: v8oClient}))
+		assert.Equal(t, "binding references cluster(s) \"east,west,east,south,central\" that do not exist in namespace default", message)
+		gets := 0
+		for _, action := range v8oClient.Actions() {
+			if action.GetVerb() == "get" {
+				gets++
+			}
+		}
+		assert.Equal(t, 5, gets)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	v8ofake "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/fake"
+	yamlv3 "gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// ReadModelFile reads a VerrazzanoModel from a YAML or JSON file
+func ReadModelFile(path string) (*v1beta1v8o.VerrazzanoModel, error) {
+	model := &v1beta1v8o.VerrazzanoModel{}
+	if err := decodeFile(path, model); err != nil {
+		return nil, err
+	}
+	if model.Kind != "VerrazzanoModel" {
+		return nil, fmt.Errorf("file %s contains a %s, expected a VerrazzanoModel", path, model.Kind)
+	}
+	return model, nil
+}
+
+// ReadBindingFile reads a VerrazzanoBinding from a YAML or JSON file
+func ReadBindingFile(path string) (*v1beta1v8o.VerrazzanoBinding, error) {
+	binding := &v1beta1v8o.VerrazzanoBinding{}
+	if err := decodeFile(path, binding); err != nil {
+		return nil, err
+	}
+	if binding.Kind != "VerrazzanoBinding" {
+		return nil, fmt.Errorf("file %s contains a %s, expected a VerrazzanoBinding", path, binding.Kind)
+	}
+	return binding, nil
+}
+
+// Decode the first document of a YAML or JSON file
+func decodeFile(path string, into interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(into); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	return nil
+}
+
+// Manifest is a resource read from a manifest file
+type Manifest struct {
+	Path   string
+	Object runtime.Object
+	// Positions of the fields of the resource in the file
+	Positions FieldPositions
+}
+
+// ReadManifests reads the resources of the kinds used by the validations from a YAML or JSON file, or from all
+// the .yaml, .yml and .json files of a directory and its subdirectories.  A file can contain several documents
+// and lists, like the output of kubectl get -o yaml.  Resources of other kinds are ignored.  Resources without
+// a namespace are put in the default namespace.
+func ReadManifests(path string) ([]Manifest, error) {
+	return ReadManifestsInNamespace(path, "default")
+}
+
+// ReadManifestsInNamespace reads the resources like ReadManifests and puts the resources without a namespace in
+// the given namespace, like kubectl does with the namespace of its context
+func ReadManifestsInNamespace(path string, namespace string) ([]Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		return readManifestFile(path, namespace)
+	}
+
+	var manifests []Manifest
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch filepath.Ext(file) {
+		case ".yaml", ".yml", ".json":
+			if info.Mode().IsRegular() {
+				fileManifests, err := readManifestFile(file, namespace)
+				if err != nil {
+					return err
+				}
+				manifests = append(manifests, fileManifests...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests %s: %v", path, err)
+	}
+	return manifests, nil
+}
+
+// Read the resources of all the documents of a manifest file, resources without a namespace are put in the
+// given namespace
+func readManifestFile(path string, namespace string) ([]Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var manifests []Manifest
+	decoder := yamlv3.NewDecoder(file)
+	for {
+		var node yamlv3.Node
+		if err := decoder.Decode(&node); err == io.EOF {
+			return manifests, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+		}
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+		}
+		positions := make(FieldPositions)
+		getFieldPositions(&node, "", positions)
+		documentManifests, err := decodeManifest(raw, positions, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+		}
+		for _, manifest := range documentManifests {
+			manifest.Path = path
+			manifests = append(manifests, manifest)
+		}
+	}
+}
+
+// Decode a resource, or the items of a list, into the typed objects of the kinds used by the validations.
+// Resources without a namespace are put in the given namespace.
+func decodeManifest(raw json.RawMessage, positions FieldPositions, namespace string) ([]Manifest, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+
+	var object runtime.Object
+	switch typeMeta.Kind {
+	case "List":
+		list := struct {
+			Items []json.RawMessage `json:"items"`
+		}{}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		var manifests []Manifest
+		for i, item := range list.Items {
+			itemManifests, err := decodeManifest(item, positions.under(fmt.Sprintf("items[%d]", i)), namespace)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, itemManifests...)
+		}
+		return manifests, nil
+	case "VerrazzanoModel":
+		object = &v1beta1v8o.VerrazzanoModel{}
+	case "VerrazzanoBinding":
+		object = &v1beta1v8o.VerrazzanoBinding{}
+	case "VerrazzanoManagedCluster":
+		object = &v1beta1v8o.VerrazzanoManagedCluster{}
+	case "Secret":
+		object = &corev1.Secret{}
+	case "ConfigMap":
+		object = &corev1.ConfigMap{}
+	case "Namespace":
+		object = &corev1.Namespace{}
+	default:
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw, object); err != nil {
+		return nil, err
+	}
+
+	if accessor, err := meta.Accessor(object); err == nil && accessor.GetNamespace() == "" && typeMeta.Kind != "Namespace" {
+		accessor.SetNamespace(namespace)
+	}
+	// The API server merges the string data of a secret into its data
+	if secret, ok := object.(*corev1.Secret); ok && len(secret.StringData) > 0 {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		for key, value := range secret.StringData {
+			secret.Data[key] = []byte(value)
+		}
+		secret.StringData = nil
+	}
+	return []Manifest{{Object: object, Positions: positions}}, nil
+}
+
+// NewManifestClientsets returns clientsets that resolve lookups from the given resources instead of a cluster.
+// When several manifests contain the same resource, the last one is used.
+func NewManifestClientsets(manifests []Manifest) *Clientsets {
+	latest := make(map[string]runtime.Object)
+	var keys []string
+	for _, manifest := range manifests {
+		accessor, err := meta.Accessor(manifest.Object)
+		if err != nil {
+			continue
+		}
+		key := fmt.Sprintf("%T/%s/%s", manifest.Object, accessor.GetNamespace(), accessor.GetName())
+		if _, ok := latest[key]; !ok {
+			keys = append(keys, key)
+		}
+		latest[key] = manifest.Object
+	}
+
+	var k8sObjects, v8oObjects []runtime.Object
+	for _, key := range keys {
+		switch latest[key].(type) {
+		case *corev1.Secret, *corev1.ConfigMap, *corev1.Namespace:
+			k8sObjects = append(k8sObjects, latest[key])
+		default:
+			v8oObjects = append(v8oObjects, latest[key])
+		}
+	}
+	return &Clientsets{
+		V8oClient: v8ofake.NewSimpleClientset(v8oObjects...).VerrazzanoV1beta1(),
+		K8sClient: k8sfake.NewSimpleClientset(k8sObjects...),
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReadModelAndBindingFiles tests reading of models and bindings from files
+// GIVEN model and binding YAML files
+//  WHEN ReadModelFile and ReadBindingFile are called
+//  THEN the model and binding should be returned or an error if the file doesn't contain the expected kind
+func TestReadModelAndBindingFiles(t *testing.T) {
+	model, err := ReadModelFile("../test/integ/testdata/bobs-books-v2-model.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "bobs-books-model", model.Name)
+
+	binding, err := ReadBindingFile("../test/integ/testdata/bobs-books-v2-binding.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "bobs-books-model", binding.Spec.ModelName)
+
+	_, err = ReadModelFile("../test/integ/testdata/bobs-books-v2-binding.yaml")
+	assert.EqualError(t, err, "file ../test/integ/testdata/bobs-books-v2-binding.yaml contains a VerrazzanoBinding, expected a VerrazzanoModel")
+
+	_, err = ReadBindingFile("../test/integ/testdata/missing.yaml")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Decisions of admission requests
+const (
+	decisionAllowed = "allowed"
+	decisionDenied  = "denied"
+	// The request could not be processed and no admission review was returned
+	decisionError = "error"
+)
+
+// Names of the API lookups whose latency is measured
+const (
+	lookupGetSecret        = "getSecret"
+	lookupGetConfigMap     = "getConfigMap"
+	lookupValidateClusters = "validateClusters"
+	lookupListModels       = "listModels"
+	lookupListBindings     = "listBindings"
+	lookupGetModel         = "getModel"
+	lookupGetNamespace     = "getNamespace"
+)
+
+var (
+	admissionRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "requests_total",
+		Help:      "Number of admission requests by kind, operation and decision.",
+	}, []string{"kind", "operation", "decision"})
+
+	admissionDenials = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "denials_total",
+		Help:      "Number of problems found in denied admission requests by kind and rule.  The rule is the ID of the check that found the problem, or unclassified for problems without a rule.",
+	}, []string{"kind", "rule"})
+
+	admissionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to process admission requests by kind and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind", "operation"})
+
+	ruleViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "rule_violations_total",
+		Help:      "Number of problems found for rules that are not enforced by kind, rule and mode.",
+	}, []string{"kind", "rule", "mode"})
+
+	lookupDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "lookup_duration_seconds",
+		Help:      "Time taken by the API lookups made by the validations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"lookup"})
+
+	validationTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "timeouts_total",
+		Help:      "Number of admission requests by kind whose validation exceeded its deadline.",
+	}, []string{"kind"})
+
+	clientsetErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "verrazzano_admission",
+		Name:      "clientset_errors_total",
+		Help:      "Number of failures to create the clientsets used by the validations.",
+	})
+)
+
+// MetricsRegistry contains the metrics of the admission controller and of the Go runtime and process
+var MetricsRegistry = prometheus.NewRegistry()
+
+func init() {
+	MetricsRegistry.MustRegister(admissionRequests, admissionDenials, ruleViolations, admissionDuration, lookupDuration, validationTimeouts, clientsetErrors,
+		prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+}
+
+// MetricsHandler serves the metrics in the Prometheus text format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(MetricsRegistry, promhttp.HandlerOpts{})
+}
+
+// Record the time taken by an API lookup started at the given time, meant to be deferred
+func observeLookup(lookup string, start time.Time) {
+	lookupDuration.WithLabelValues(lookup).Observe(time.Since(start).Seconds())
+}
+
+// Record the decision and latency of an admission request, and the rules of the problems of a denied request
+func recordAdmission(kind string, operation string, decision string, problems []Problem, start time.Time) {
+	admissionRequests.WithLabelValues(kind, operation, decision).Inc()
+	admissionDuration.WithLabelValues(kind, operation).Observe(time.Since(start).Seconds())
+	if decision != decisionDenied {
+		return
+	}
+	for _, problem := range problems {
+		admissionDenials.WithLabelValues(kind, problemRule(problem)).Inc()
+	}
+}
+
+// Get the rule of a problem used as a metric label, problems of validators that don't set a rule are unclassified
+func problemRule(problem Problem) string {
+	if problem.Rule == "" {
+		return ruleUnclassified
+	}
+	return problem.Rule
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	v8ofake "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Get the number of observations of a histogram
+func histogramCount(t *testing.T, histogram *prometheus.HistogramVec, labels ...string) uint64 {
+	metric := &dto.Metric{}
+	assert.Nil(t, histogram.WithLabelValues(labels...).(prometheus.Histogram).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+// TestRecordAdmission tests recording of admission decisions
+// GIVEN allowed and denied admission requests
+//  WHEN recordAdmission is called
+//  THEN the requests should be counted by decision, their latency observed and the problems of denied requests
+//	 counted by rule
+func TestRecordAdmission(t *testing.T) {
+	allowed := testutil.ToFloat64(admissionRequests.WithLabelValues("VerrazzanoBinding", "CREATE", decisionAllowed))
+	denied := testutil.ToFloat64(admissionRequests.WithLabelValues("VerrazzanoBinding", "CREATE", decisionDenied))
+	replicas := testutil.ToFloat64(admissionDenials.WithLabelValues("VerrazzanoBinding", ruleBindingReplicas))
+	unclassified := testutil.ToFloat64(admissionDenials.WithLabelValues("VerrazzanoBinding", ruleUnclassified))
+	observations := histogramCount(t, admissionDuration, "VerrazzanoBinding", "CREATE")
+
+	recordAdmission("VerrazzanoBinding", "CREATE", decisionAllowed, nil, time.Now())
+	recordAdmission("VerrazzanoBinding", "CREATE", decisionDenied, append(withRule(ruleBindingReplicas, []Problem{
+		fieldProblem("spec.helidonBindings[0].replicas", problemTypeInvalid, "12: must be less than or equal to 10"),
+		fieldProblem("spec.helidonBindings[2].replicas", problemTypeInvalid, "11: must be less than or equal to 10"),
+	}), newProblem("binding references cluster(s) \"local\" that do not exist in namespace default")), time.Now())
+
+	assert.Equal(t, allowed+1, testutil.ToFloat64(admissionRequests.WithLabelValues("VerrazzanoBinding", "CREATE", decisionAllowed)))
+	assert.Equal(t, denied+1, testutil.ToFloat64(admissionRequests.WithLabelValues("VerrazzanoBinding", "CREATE", decisionDenied)))
+	assert.Equal(t, replicas+2, testutil.ToFloat64(admissionDenials.WithLabelValues("VerrazzanoBinding", ruleBindingReplicas)))
+	assert.Equal(t, unclassified+1, testutil.ToFloat64(admissionDenials.WithLabelValues("VerrazzanoBinding", ruleUnclassified)))
+	assert.Equal(t, observations+2, histogramCount(t, admissionDuration, "VerrazzanoBinding", "CREATE"))
+}
+
+// TestObserveLookups tests observation of the latency of API lookups
+// GIVEN clientsets
+//  WHEN models, bindings and secrets are looked up
+//  THEN the latency of each lookup should be observed
+func TestObserveLookups(t *testing.T) {
+	clientsets := &Clientsets{V8oClient: v8ofake.NewSimpleClientset().VerrazzanoV1beta1(), K8sClient: k8sfake.NewSimpleClientset()}
+	models := histogramCount(t, lookupDuration, lookupListModels)
+	bindings := histogramCount(t, lookupDuration, lookupListBindings)
+	secrets := histogramCount(t, lookupDuration, lookupGetSecret)
+
+	_, err := listModels(context.TODO(), clientsets, "default")
+	assert.Nil(t, err)
+	_, err = listBindings(context.TODO(), clientsets, "default")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, getSecret(context.TODO(), clientsets, "missing", "secret", "component"))
+
+	assert.Equal(t, models+1, histogramCount(t, lookupDuration, lookupListModels))
+	assert.Equal(t, bindings+1, histogramCount(t, lookupDuration, lookupListBindings))
+	assert.Equal(t, secrets+1, histogramCount(t, lookupDuration, lookupGetSecret))
+}
+
+// TestMetricsHandler tests serving of the metrics
+// GIVEN a metrics handler
+//  WHEN the metrics are requested
+//  THEN the admission controller metrics should be returned in the Prometheus text format
+func TestMetricsHandler(t *testing.T) {
+	recordAdmission("VerrazzanoModel", "CREATE", decisionAllowed, nil, time.Now())
+	recorder := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `verrazzano_admission_requests_total{decision="allowed",kind="VerrazzanoModel",operation="CREATE"}`)
+	assert.Contains(t, recorder.Body.String(), "verrazzano_admission_request_duration_seconds_bucket")
+	assert.Contains(t, recorder.Body.String(), "go_goroutines")
+}
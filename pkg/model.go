@@ -5,158 +5,161 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path"
 	s "strings"
+	"time"
 
 	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
 	"go.uber.org/zap"
 	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sValidations "k8s.io/apimachinery/pkg/util/validation"
 )
 
-func validateModel(model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) v1beta1.AdmissionReview {
-	zap.S().Debugw("In validateModel code")
+const (
+	// Port the WebLogic admin server listens on when adminPort is not set
+	defaultWebLogicAdminServerPort = 7001
+	// Port the WebLogic managed servers listen on
+	defaultWebLogicManagedServerPort = 8001
+)
 
-	response := validateModelResourceNames(model)
-	if response != "" {
-		return errorAdmissionReview(response)
-	}
+// Validates the creation, update and deletion of models
+type modelValidator struct{}
 
-	response = validateSingleWebLogicCluster(model)
-	if response != "" {
-		return errorAdmissionReview(response)
-	}
+func (modelValidator) Registration() ValidatorRegistration {
+	return ValidatorRegistration{Kind: "VerrazzanoModel", Group: "verrazzano.io", Version: "v1beta1", Resource: "verrazzanomodels",
+		Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update, admissionv1beta1.Delete}}
+}
 
-	// All secrets in the model must be defined in the default namespace.
-	response = validateModelSecrets(model, clientsets)
-	if response != "" {
-		return errorAdmissionReview(response)
+func (modelValidator) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	if request.Operation == v1beta1.Delete {
+		zap.S().Infof("processing model name: %s:%s", request.Namespace, request.Name)
+		return problemsResult(deleteModel(ctx, v1beta1.AdmissionReview{Request: request.AdmissionRequest}, request.Clientsets))
 	}
-
-	response = validateWebLogicDomains(model)
-	if response != "" {
-		return errorAdmissionReview(response)
+	model := v1beta1v8o.VerrazzanoModel{}
+	if err := json.Unmarshal(request.Object.Raw, &model); err != nil {
+		zap.S().Errorf("error with unmarshal of VerrazzanoModel: %v", err)
+		return DenyProblems(withRule(ruleInvalidObject, []Problem{newProblem("error with unmarshal of VerrazzanoModel: %v", err)})...)
 	}
+	zap.S().Infof("processing model name: %s:%s", model.Namespace, model.Name)
+	return problemsResult(validateModel(ctx, model, request.Clientsets, request.Policy))
+}
 
-	response = validateCoherenceClusters(model)
-	if response != "" {
-		return errorAdmissionReview(response)
-	}
+func validateModel(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets, policy *Policy) []Problem {
+	zap.S().Debugw("In validateModel code")
 
-	response = validateHelidonApplications(model)
-	if response != "" {
-		return errorAdmissionReview(response)
-	}
+	// Every check runs so that the problems of the rules that are not enforced don't hide the others
+	problems := withRule(ruleResourceNames, validateModelResourceNames(model))
+	problems = append(problems, withRule(ruleWebLogicClusters, validateWebLogicClusters(model, policy.ForNamespace(model.Namespace)))...)
 
-	response = validateGenericComponents(model)
-	if response != "" {
-		return errorAdmissionReview(response)
+	// All secrets in the model must be defined in the default namespace.
+	problems = append(problems, withRule(ruleSecretReferences, validateModelSecrets(ctx, model, clientsets))...)
+
+	// All config maps in the model must be defined in the default namespace.
+	problems = append(problems, withRule(ruleConfigMapReferences, validateModelConfigMaps(ctx, model, clientsets))...)
+
+	problems = append(problems, withRule(ruleWebLogicDomains, validateWebLogicDomains(model))...)
+	problems = append(problems, withRule(ruleWebLogicDomainValues, validateWebLogicDomainValues(ctx, model, clientsets))...)
+	problems = append(problems, withRule(ruleCoherenceClusters, validateCoherenceClusters(model))...)
+	problems = append(problems, withRule(ruleCoherenceClusters, validateCoherenceConnections(ctx, model, clientsets))...)
+	problems = append(problems, withRule(ruleHelidonApplications, validateHelidonApplications(model))...)
+	problems = append(problems, withRule(ruleHelidonPlacementPorts, validateHelidonPlacements(ctx, model, clientsets))...)
+	problems = append(problems, withRule(ruleGenericComponents, validateGenericComponents(model))...)
+	if len(problems) > 0 {
+		return problems
 	}
 
 	zap.S().Infow("validation of model successful")
-	return v1beta1.AdmissionReview{}
+	return nil
 }
 
-func deleteModel(arRequest v1beta1.AdmissionReview, clientsets *Clientsets) v1beta1.AdmissionReview {
+func deleteModel(ctx context.Context, arRequest v1beta1.AdmissionReview, clientsets *Clientsets) []Problem {
 	zap.S().Debugw("In deleteModel code")
 
 	// Delete is being called for namespaces (for some unknown reason) when there is single cluster.  In this case,
 	// there is no resource name so just return and don't generate an error.
 	if len(arRequest.Request.Name) == 0 {
-		_, err := clientsets.K8sClient.CoreV1().Namespaces().Get(context.TODO(), arRequest.Request.Namespace, metav1.GetOptions{})
+		start := time.Now()
+		_, err := clientsets.K8sClient.CoreV1().Namespaces().Get(ctx, arRequest.Request.Namespace, metav1.GetOptions{})
+		observeLookup(lookupGetNamespace, start)
+		recordTimedOutLookup(ctx, err, "namespace "+arRequest.Request.Namespace)
 		if err == nil {
 			zap.S().Infow("delete of namespace was requested, no model to delete")
-			return v1beta1.AdmissionReview{}
+			return nil
 		}
 	}
 
 	// Get the model we want to delete
-	model, err := clientsets.V8oClient.VerrazzanoModels(arRequest.Request.Namespace).Get(context.TODO(), arRequest.Request.Name, metav1.GetOptions{})
+	model, err := getModel(ctx, clientsets, arRequest.Request.Namespace, arRequest.Request.Name)
 
 	// Delete is called for resources that don't exist. If that is the case, then just return
 	if k8sErrors.IsNotFound(err) {
 		zap.S().Infow("model does not exist, nothing to delete")
-		return v1beta1.AdmissionReview{}
+		return nil
 	}
 
 	// Don't allow delete if we had an error getting the model
 	if err != nil {
-		message := fmt.Sprintf("error getting model for namespace %s: %v", arRequest.Request.Namespace, err)
-		zap.S().Errorw(message)
-		return errorAdmissionReview(message)
+		problem := newProblem("error getting model for namespace %s: %v", arRequest.Request.Namespace, err)
+		zap.S().Errorw(problem.Message)
+		return withRule(ruleModelInUse, []Problem{problem})
 	}
 
 	// Don't allow delete if a deployed binding references this model
 	if model != nil {
-		bindingList, err := clientsets.V8oClient.VerrazzanoBindings(arRequest.Request.Namespace).List(context.TODO(), metav1.ListOptions{})
+		bindingList, err := listBindings(ctx, clientsets, arRequest.Request.Namespace)
 		if err == nil && bindingList != nil {
 			for _, binding := range bindingList.Items {
 				if binding.Spec.ModelName == model.Name {
-					message := fmt.Sprintf("model cannot be deleted before binding %s is deleted in namespace %s", binding.Name, arRequest.Request.Namespace)
-					zap.S().Errorw(message)
-					return errorAdmissionReview(message)
+					problem := newProblem("model cannot be deleted before binding %s is deleted in namespace %s", binding.Name, arRequest.Request.Namespace)
+					zap.S().Errorw(problem.Message)
+					return withRule(ruleModelInUse, []Problem{problem})
 				}
 			}
 		}
 	}
 
 	zap.S().Infow("validation of model successful")
-	return v1beta1.AdmissionReview{}
+	return nil
 }
 
 // Validate names that will be used as Kubernetes resource names.
 // A validate k8s resource name must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an
 // alphanumeric character.  We use k8s validation functions to check the validity of names.
-func validateModelResourceNames(model v1beta1v8o.VerrazzanoModel) string {
+func validateModelResourceNames(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateModelResourceNames code")
 
-	var errMessages []string
-
-	for _, msg := range validateModelHelidonNames(model) {
-		errMessages = append(errMessages, msg)
-	}
-
-	for _, msg := range validateModelCoherenceNames(model) {
-		errMessages = append(errMessages, msg)
-	}
+	var errMessages []Problem
 
-	for _, msg := range validateModelWeblogicNames(model) {
-		errMessages = append(errMessages, msg)
-	}
+	errMessages = append(errMessages, validateModelHelidonNames(model)...)
+	errMessages = append(errMessages, validateModelCoherenceNames(model)...)
+	errMessages = append(errMessages, validateModelWeblogicNames(model)...)
+	errMessages = append(errMessages, validateModelGenericComponentNames(model)...)
+	errMessages = append(errMessages, validateModelAllIngressNames(model)...)
 
-	for _, msg := range validateModelGenericComponentNames(model) {
-		errMessages = append(errMessages, msg)
-	}
-
-	for _, msg := range validateModelAllIngressNames(model) {
-		errMessages = append(errMessages, msg)
-	}
-
-	if len(errMessages) > 0 {
-		return s.Join(errMessages, "")
-	}
-
-	return ""
+	return errMessages
 }
 
 // Validate names for Helidon applications
-func validateModelHelidonNames(model v1beta1v8o.VerrazzanoModel) []string {
+func validateModelHelidonNames(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateModelHelidonNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	for i, ha := range model.Spec.HelidonApplications {
 		// Check the Helidon component name
 		field := fmt.Sprintf("spec.helidonApplications[%d].name", i)
-		errMessages = addInvalidNameFormatMessage(ha.Name, field, errMessages)
+		errMessages = addInvalidNameProblems(ha.Name, field, errMessages)
 
 		// Check the Helidon imagePullSecrets name
 		for k, secret := range ha.ImagePullSecrets {
 			field := fmt.Sprintf("spec.helidonApplications[%d].imagePullSecrets[%d].name", i, k)
-			errMessages = addInvalidNameFormatMessage(secret.Name, field, errMessages)
+			errMessages = addInvalidNameProblems(secret.Name, field, errMessages)
 		}
 	}
 
@@ -164,20 +167,20 @@ func validateModelHelidonNames(model v1beta1v8o.VerrazzanoModel) []string {
 }
 
 // Validate names for Coherence clusters
-func validateModelCoherenceNames(model v1beta1v8o.VerrazzanoModel) []string {
+func validateModelCoherenceNames(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateModelCoherenceNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	for i, cc := range model.Spec.CoherenceClusters {
 		// Check the Coherence component name
 		field := fmt.Sprintf("spec.coherenceClusters[%d].name", i)
-		errMessages = addInvalidNameFormatMessage(cc.Name, field, errMessages)
+		errMessages = addInvalidNameProblems(cc.Name, field, errMessages)
 
 		// Check the Coherence imagePullSecrets name
 		for k, secret := range cc.ImagePullSecrets {
 			field := fmt.Sprintf("spec.coherenceClusters[%d].imagePullSecrets[%d].name", i, k)
-			errMessages = addInvalidNameFormatMessage(secret.Name, field, errMessages)
+			errMessages = addInvalidNameProblems(secret.Name, field, errMessages)
 		}
 	}
 
@@ -185,43 +188,53 @@ func validateModelCoherenceNames(model v1beta1v8o.VerrazzanoModel) []string {
 }
 
 // Validate names for WebLogic domains
-func validateModelWeblogicNames(model v1beta1v8o.VerrazzanoModel) []string {
+func validateModelWeblogicNames(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateModelWeblogicNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	for i, domain := range model.Spec.WeblogicDomains {
 		// Check the WebLogic component name
 		field := fmt.Sprintf("spec.weblogicDomains[%d].name", i)
-		errMessages = addInvalidNameFormatMessage(domain.Name, field, errMessages)
+		errMessages = addInvalidNameProblems(domain.Name, field, errMessages)
 
 		// Check the WebLogic domain UID name
 		if len(domain.DomainCRValues.DomainUID) > 0 {
 			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.domainUID", i)
-			errMessages = addInvalidNameFormatMessage(domain.DomainCRValues.DomainUID, field, errMessages)
+			errMessages = addInvalidNameProblems(domain.DomainCRValues.DomainUID, field, errMessages)
 		}
 
 		// Check the WebLogic imagePullSecrets name
 		for j, secret := range domain.DomainCRValues.ImagePullSecrets {
 			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.imagePullSecrets[%d].name", i, j)
-			errMessages = addInvalidNameFormatMessage(secret.Name, field, errMessages)
+			errMessages = addInvalidNameProblems(secret.Name, field, errMessages)
 		}
 
 		// Check the webLogicCredentialsSecret name
 		secret := domain.DomainCRValues.WebLogicCredentialsSecret
 		field = fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.webLogicCredentialsSecret.name", i)
-		errMessages = addInvalidNameFormatMessage(secret.Name, field, errMessages)
+		errMessages = addInvalidNameProblems(secret.Name, field, errMessages)
 
 		// Check the WebLogic configOverrideSecrets name
 		for j, secret := range domain.DomainCRValues.ConfigOverrideSecrets {
 			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configOverrideSecrets[%d]", i, j)
-			errMessages = addInvalidNameFormatMessage(secret, field, errMessages)
+			errMessages = addInvalidNameProblems(secret, field, errMessages)
 		}
 
 		// Check the WebLogic configuration secrets name
 		for j, secret := range domain.DomainCRValues.Configuration.Secrets {
 			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configuration.secrets[%d]", i, j)
-			errMessages = addInvalidNameFormatMessage(secret, field, errMessages)
+			errMessages = addInvalidNameProblems(secret, field, errMessages)
+		}
+
+		// Check the WebLogic configuration config map names
+		if configuration := domain.DomainCRValues.Configuration; configuration.OverridesConfigMap != "" {
+			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configuration.overridesConfigMap", i)
+			errMessages = addInvalidNameProblems(configuration.OverridesConfigMap, field, errMessages)
+		}
+		if configuration := domain.DomainCRValues.Configuration; configuration.Model.ConfigMap != "" {
+			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configuration.model.configMap", i)
+			errMessages = addInvalidNameProblems(configuration.Model.ConfigMap, field, errMessages)
 		}
 	}
 
@@ -229,40 +242,68 @@ func validateModelWeblogicNames(model v1beta1v8o.VerrazzanoModel) []string {
 }
 
 // Validate names for generic components
-func validateModelGenericComponentNames(model v1beta1v8o.VerrazzanoModel) []string {
+func validateModelGenericComponentNames(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateModelGenericComponentNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	for i, generic := range model.Spec.GenericComponents {
 		// Check the generic component name
 		field := fmt.Sprintf("spec.genericComponents[%d].name", i)
-		errMessages = addInvalidNameFormatMessage(generic.Name, field, errMessages)
+		errMessages = addInvalidNameProblems(generic.Name, field, errMessages)
 
 		// Check the generic component imagePullSecrets name
 		for j, secret := range generic.Deployment.ImagePullSecrets {
 			field := fmt.Sprintf("spec.genericComponents[%d].deployment.imagePullSecrets[%d].name", i, j)
-			errMessages = addInvalidNameFormatMessage(secret.Name, field, errMessages)
+			errMessages = addInvalidNameProblems(secret.Name, field, errMessages)
 		}
 
-		// Check the generic component deployment containers for secret name references
+		// Check the generic component deployment containers for secret and config map name references
 		for j, container := range generic.Deployment.Containers {
 			for k, env := range container.Env {
 				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
 					field := fmt.Sprintf("spec.genericComponents[%d].deployment.containers[%d].env[%d].valueFrom.secretKeyRef.name", i, j, k)
-					errMessages = addInvalidNameFormatMessage(env.ValueFrom.SecretKeyRef.Name, field, errMessages)
+					errMessages = addInvalidNameProblems(env.ValueFrom.SecretKeyRef.Name, field, errMessages)
+				}
+				if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+					field := fmt.Sprintf("spec.genericComponents[%d].deployment.containers[%d].env[%d].valueFrom.configMapKeyRef.name", i, j, k)
+					errMessages = addInvalidNameProblems(env.ValueFrom.ConfigMapKeyRef.Name, field, errMessages)
+				}
+			}
+			for k, envFrom := range container.EnvFrom {
+				if envFrom.ConfigMapRef != nil {
+					field := fmt.Sprintf("spec.genericComponents[%d].deployment.containers[%d].envFrom[%d].configMapRef.name", i, j, k)
+					errMessages = addInvalidNameProblems(envFrom.ConfigMapRef.Name, field, errMessages)
 				}
 			}
 		}
 
-		// Check the generic component deployment init containers for secret name references
+		// Check the generic component deployment init containers for secret and config map name references
 		for j, container := range generic.Deployment.InitContainers {
 			for k, env := range container.Env {
 				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
 					field := fmt.Sprintf("spec.genericComponents[%d].deployment.initContainers[%d].env[%d].valueFrom.secretKeyRef.name", i, j, k)
-					errMessages = addInvalidNameFormatMessage(env.ValueFrom.SecretKeyRef.Name, field, errMessages)
+					errMessages = addInvalidNameProblems(env.ValueFrom.SecretKeyRef.Name, field, errMessages)
+				}
+				if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+					field := fmt.Sprintf("spec.genericComponents[%d].deployment.initContainers[%d].env[%d].valueFrom.configMapKeyRef.name", i, j, k)
+					errMessages = addInvalidNameProblems(env.ValueFrom.ConfigMapKeyRef.Name, field, errMessages)
 				}
 			}
+			for k, envFrom := range container.EnvFrom {
+				if envFrom.ConfigMapRef != nil {
+					field := fmt.Sprintf("spec.genericComponents[%d].deployment.initContainers[%d].envFrom[%d].configMapRef.name", i, j, k)
+					errMessages = addInvalidNameProblems(envFrom.ConfigMapRef.Name, field, errMessages)
+				}
+			}
+		}
+
+		// Check the generic component deployment volumes for config map name references
+		for j, volume := range generic.Deployment.Volumes {
+			if volume.ConfigMap != nil {
+				field := fmt.Sprintf("spec.genericComponents[%d].deployment.volumes[%d].configMap.name", i, j)
+				errMessages = addInvalidNameProblems(volume.ConfigMap.Name, field, errMessages)
+			}
 		}
 
 	}
@@ -271,10 +312,10 @@ func validateModelGenericComponentNames(model v1beta1v8o.VerrazzanoModel) []stri
 }
 
 // Validate ingress connection names for all components
-func validateModelAllIngressNames(model v1beta1v8o.VerrazzanoModel) []string {
+func validateModelAllIngressNames(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateModelAllIngressNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	// Check the Helidon applications ingress names
 	for i, ha := range model.Spec.HelidonApplications {
@@ -320,250 +361,974 @@ func validateModelAllIngressNames(model v1beta1v8o.VerrazzanoModel) []string {
 }
 
 // Validate ingress connections names
-func validateModelIngressNames(connections []v1beta1v8o.VerrazzanoIngressConnection, prefix string) []string {
+func validateModelIngressNames(connections []v1beta1v8o.VerrazzanoIngressConnection, prefix string) []Problem {
 	zap.S().Debugw("In validateModelIngressNames code")
 
-	var errMessages []string
+	var errMessages []Problem
 
 	for i, ingress := range connections {
 		field := fmt.Sprintf("%s.ingress[%d].name", prefix, i)
-		errMessages = addInvalidNameFormatMessage(ingress.Name, field, errMessages)
+		errMessages = addInvalidNameProblems(ingress.Name, field, errMessages)
 	}
 
 	return errMessages
 }
 
-// Validate that each secret in the model has a matching secret in the default namespace
-func validateModelSecrets(model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) string {
+// Validate that each secret in the model has a matching secret in the default namespace.  Each referenced secret
+// is fetched once, concurrently, and the problem of the first reference in the model is reported.
+func validateModelSecrets(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
 	zap.S().Debugw("In validateModelSecrets code")
 
-	// Check image pull secrets for Helidon applications
-	for _, ha := range model.Spec.HelidonApplications {
-		for _, secret := range ha.ImagePullSecrets {
-			message := getSecret(clientsets, secret.Name, "helidonApplications.imagePullSecret", ha.Name)
-			if message != "" {
-				return message
-			}
+	// The first component referencing a secret describes its lookup when it times out
+	references := modelSecretReferences(model)
+	var names []string
+	for _, reference := range references {
+		names = append(names, reference.name)
+	}
+	names, indexes := distinctValues(names)
+	components := make([]string, len(names))
+	for _, reference := range references {
+		if i := indexes[reference.name]; components[i] == "" {
+			components[i] = reference.component
 		}
 	}
+	secrets := make([]*corev1.Secret, len(names))
+	errs := make([]error, len(names))
+	forEachConcurrently(len(names), func(i int) {
+		secrets[i], errs[i] = fetchSecret(ctx, clientsets, names[i], components[i])
+	})
+
+	for _, reference := range references {
+		i := indexes[reference.name]
+		if problems := checkSecretReference(reference, secrets[i], errs[i]); len(problems) > 0 {
+			return problems
+		}
+	}
+	return nil
+}
 
-	// Check image pull secrets for Coherence clusters
-	for _, cc := range model.Spec.CoherenceClusters {
-		for _, secret := range cc.ImagePullSecrets {
-			message := getSecret(clientsets, secret.Name, "coherenceClusters.imagePullSecret", cc.Name)
-			if message != "" {
-				return message
-			}
+// A reference of a component of a model to a secret of the default namespace
+type secretReference struct {
+	name string
+	// Path of the reference in the model, empty if not known
+	field string
+	// Type of the reference, used in messages
+	secretType string
+	component  string
+	// Whether the type of the secret must be usable to pull images
+	imagePull bool
+}
+
+// Get the references of a model to secrets, in the order they are validated
+func modelSecretReferences(model v1beta1v8o.VerrazzanoModel) []secretReference {
+	var references []secretReference
+
+	// Image pull secrets for Helidon applications
+	for i, ha := range model.Spec.HelidonApplications {
+		for j, secret := range ha.ImagePullSecrets {
+			references = append(references, secretReference{name: secret.Name, field: fmt.Sprintf("spec.helidonApplications[%d].imagePullSecrets[%d]", i, j),
+				secretType: "helidonApplications.imagePullSecret", component: ha.Name})
 		}
 	}
 
-	// Check image pull secrets for WebLogic domains
-	for _, domain := range model.Spec.WeblogicDomains {
-		for _, secret := range domain.DomainCRValues.ImagePullSecrets {
-			message := getSecret(clientsets, secret.Name, "weblogicDomains.domainCRValues.imagePullSecret", domain.Name)
-			if message != "" {
-				return message
-			}
+	// Image pull secrets for Coherence clusters
+	for i, cc := range model.Spec.CoherenceClusters {
+		for j, secret := range cc.ImagePullSecrets {
+			references = append(references, secretReference{name: secret.Name, field: fmt.Sprintf("spec.coherenceClusters[%d].imagePullSecrets[%d]", i, j),
+				secretType: "coherenceClusters.imagePullSecret", component: cc.Name, imagePull: true})
 		}
 	}
 
-	// Check WebLogic domain credential secrets
-	for _, cred := range model.Spec.WeblogicDomains {
-		secret := cred.DomainCRValues.WebLogicCredentialsSecret
-		message := getSecret(clientsets, secret.Name, "weblogicDomains.domainCRValues.webLogicCredentialsSecret", cred.Name)
-		if message != "" {
-			return message
+	// Image pull secrets for WebLogic domains
+	for i, domain := range model.Spec.WeblogicDomains {
+		for j, secret := range domain.DomainCRValues.ImagePullSecrets {
+			references = append(references, secretReference{name: secret.Name, field: fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.imagePullSecrets[%d]", i, j),
+				secretType: "weblogicDomains.domainCRValues.imagePullSecret", component: domain.Name})
 		}
 	}
 
-	// Check WebLogic domain config override secrets
-	for _, configOverride := range model.Spec.WeblogicDomains {
-		for _, secret := range configOverride.DomainCRValues.ConfigOverrideSecrets {
-			message := getSecret(clientsets, secret, "weblogicDomains.domainCRValues.configOverrideSecrets", configOverride.Name)
-			if message != "" {
-				return message
-			}
+	// WebLogic domain credential secrets
+	for i, cred := range model.Spec.WeblogicDomains {
+		references = append(references, secretReference{name: cred.DomainCRValues.WebLogicCredentialsSecret.Name,
+			field:      fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.webLogicCredentialsSecret", i),
+			secretType: "weblogicDomains.domainCRValues.webLogicCredentialsSecret", component: cred.Name})
+	}
+
+	// WebLogic domain config override secrets
+	for i, configOverride := range model.Spec.WeblogicDomains {
+		for j, secret := range configOverride.DomainCRValues.ConfigOverrideSecrets {
+			references = append(references, secretReference{name: secret, field: fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configOverrideSecrets[%d]", i, j),
+				secretType: "weblogicDomains.domainCRValues.configOverrideSecrets", component: configOverride.Name})
 		}
 	}
 
-	// Check WebLogic domain configuration secrets
-	for _, configurationSecrets := range model.Spec.WeblogicDomains {
-		for _, secret := range configurationSecrets.DomainCRValues.Configuration.Secrets {
-			message := getSecret(clientsets, secret, "weblogicDomains.domainCRValues.configuration.secrets", configurationSecrets.Name)
-			if message != "" {
-				return message
-			}
+	// WebLogic domain configuration secrets
+	for i, configurationSecrets := range model.Spec.WeblogicDomains {
+		for j, secret := range configurationSecrets.DomainCRValues.Configuration.Secrets {
+			references = append(references, secretReference{name: secret, field: fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configuration.secrets[%d]", i, j),
+				secretType: "weblogicDomains.domainCRValues.configuration.secrets", component: configurationSecrets.Name})
 		}
 	}
 
-	// Check GenericComponents' secrets
-	for _, gc := range model.Spec.GenericComponents {
-		for _, sec := range gc.Deployment.ImagePullSecrets {
-			message := getSecret(clientsets, sec.Name, "genericComponents.Deployment.Template.Spec.ImagePullSecrets", gc.Name)
-			if message != "" {
-				return message
-			}
+	// GenericComponents' secrets
+	for i, gc := range model.Spec.GenericComponents {
+		prefix := fmt.Sprintf("spec.genericComponents[%d].deployment", i)
+		for j, sec := range gc.Deployment.ImagePullSecrets {
+			references = append(references, secretReference{name: sec.Name, field: fmt.Sprintf("%s.imagePullSecrets[%d]", prefix, j),
+				secretType: "genericComponents.Deployment.Template.Spec.ImagePullSecrets", component: gc.Name})
 		}
-		for _, container := range gc.Deployment.InitContainers {
-			message := validateContainerEnv(container, "genericComponents.Deployment.InitContainers.Env", gc.Name, clientsets)
-			if message != "" {
-				return message
-			}
+		for j, container := range gc.Deployment.InitContainers {
+			references = append(references, containerEnvSecretReferences(container, fmt.Sprintf("%s.initContainers[%d]", prefix, j), "genericComponents.Deployment.InitContainers.Env", gc.Name)...)
 		}
-		for _, container := range gc.Deployment.Containers {
-			message := validateContainerEnv(container, "genericComponents.Deployment.Containers.Env", gc.Name, clientsets)
-			if message != "" {
-				return message
-			}
+		for j, container := range gc.Deployment.Containers {
+			references = append(references, containerEnvSecretReferences(container, fmt.Sprintf("%s.containers[%d]", prefix, j), "genericComponents.Deployment.Containers.Env", gc.Name)...)
 		}
 	}
 
-	return ""
+	return references
 }
 
 // Get a secret and check for errors
-func getSecret(clientsets *Clientsets, secretName string, secretType string, compName string) string {
+func getSecret(ctx context.Context, clientsets *Clientsets, secretName string, secretType string, compName string) []Problem {
 	zap.S().Debugw("In getSecret code")
 
-	_, err := clientsets.K8sClient.CoreV1().Secrets("default").Get(context.TODO(), secretName, metav1.GetOptions{})
+	secret, err := fetchSecret(ctx, clientsets, secretName, compName)
+	return checkSecretReference(secretReference{name: secretName, secretType: secretType, component: compName}, secret, err)
+}
+
+// Check the result of fetching a secret referenced by a model, and that the type of an image pull secret can be
+// used to pull images
+func checkSecretReference(reference secretReference, secret *corev1.Secret, err error) []Problem {
 	if k8sErrors.IsNotFound(err) {
-		message := fmt.Sprintf("model references %s \"%s\" for component %s.  This secret must be created in the default namespace before proceeding.", secretType, secretName, compName)
-		zap.S().Errorw(message)
-		return message
+		problem := newFieldProblem(reference.field, problemTypeNotFound, "model references %s \"%s\" for component %s.  This secret must be created in the default namespace before proceeding.", reference.secretType, reference.name, reference.component)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
 	}
 	if err != nil {
-		message := fmt.Sprintf("failed to get referenced secret %s in namespace default: %v", secretName, err)
-		zap.S().Errorw(message)
-		return message
+		problem := newFieldProblem(reference.field, "", "failed to get referenced secret %s in namespace default: %v", reference.name, err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+	if reference.imagePull && secret.Type != corev1.SecretTypeDockerConfigJson && secret.Type != corev1.SecretTypeDockercfg {
+		problem := newFieldProblem(reference.field, problemTypeInvalid, "model references %s \"%s\" for component %s which has type %s.  Image pull secrets must have type %s or %s.", reference.secretType, reference.name, reference.component, secret.Type, corev1.SecretTypeDockerConfigJson, corev1.SecretTypeDockercfg)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
 	}
+	return nil
+}
 
-	return ""
+// Fetch a secret from the default namespace referenced by a component
+func fetchSecret(ctx context.Context, clientsets *Clientsets, secretName string, compName string) (*corev1.Secret, error) {
+	defer observeLookup(lookupGetSecret, time.Now())
+	secret, err := clientsets.K8sClient.CoreV1().Secrets("default").Get(ctx, secretName, metav1.GetOptions{})
+	recordTimedOutLookup(ctx, err, fmt.Sprintf("secret %s of component %s", secretName, compName))
+	return secret, err
+}
+
+// Validate that each config map in the model has a matching config map in the default namespace.  Each referenced
+// config map is fetched once, concurrently, and the problem of the first reference in the model is reported.
+func validateModelConfigMaps(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
+	zap.S().Debugw("In validateModelConfigMaps code")
+
+	// The first component referencing a config map describes its lookup when it times out
+	references := modelConfigMapReferences(model)
+	var names []string
+	for _, reference := range references {
+		names = append(names, reference.name)
+	}
+	names, indexes := distinctValues(names)
+	components := make([]string, len(names))
+	for _, reference := range references {
+		if i := indexes[reference.name]; components[i] == "" {
+			components[i] = reference.component
+		}
+	}
+	configMaps := make([]*corev1.ConfigMap, len(names))
+	errs := make([]error, len(names))
+	forEachConcurrently(len(names), func(i int) {
+		configMaps[i], errs[i] = fetchConfigMap(ctx, clientsets, names[i], components[i])
+	})
+
+	for _, reference := range references {
+		i := indexes[reference.name]
+		if problems := checkConfigMapReference(reference, configMaps[i], errs[i]); len(problems) > 0 {
+			return problems
+		}
+	}
+	return nil
 }
 
-func validateCoherenceClusters(model v1beta1v8o.VerrazzanoModel) string {
+// A reference of a component of a model to a config map of the default namespace
+type configMapReference struct {
+	name string
+	// Path of the reference in the model
+	field string
+	// Keys the config map must contain
+	keys []string
+	// Type of the reference, used in messages
+	configMapType string
+	component     string
+}
+
+// Get the required references of a model to config maps, in the order they are validated
+func modelConfigMapReferences(model v1beta1v8o.VerrazzanoModel) []configMapReference {
+	var references []configMapReference
+
+	// WebLogic domain configuration config maps
+	for i, domain := range model.Spec.WeblogicDomains {
+		configuration := domain.DomainCRValues.Configuration
+		prefix := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.configuration", i)
+		if configuration.OverridesConfigMap != "" {
+			references = append(references, configMapReference{name: configuration.OverridesConfigMap, field: prefix + ".overridesConfigMap",
+				configMapType: "weblogicDomains.domainCRValues.configuration.overridesConfigMap", component: domain.Name})
+		}
+		if configuration.Model.ConfigMap != "" {
+			references = append(references, configMapReference{name: configuration.Model.ConfigMap, field: prefix + ".model.configMap",
+				configMapType: "weblogicDomains.domainCRValues.configuration.model.configMap", component: domain.Name})
+		}
+	}
+
+	// GenericComponents' config maps
+	for i, gc := range model.Spec.GenericComponents {
+		prefix := fmt.Sprintf("spec.genericComponents[%d].deployment", i)
+		for j, container := range gc.Deployment.InitContainers {
+			references = append(references, containerConfigMapReferences(container, fmt.Sprintf("%s.initContainers[%d]", prefix, j), "genericComponents.Deployment.InitContainers", gc.Name)...)
+		}
+		for j, container := range gc.Deployment.Containers {
+			references = append(references, containerConfigMapReferences(container, fmt.Sprintf("%s.containers[%d]", prefix, j), "genericComponents.Deployment.Containers", gc.Name)...)
+		}
+		for j, volume := range gc.Deployment.Volumes {
+			if volume.ConfigMap == nil || isOptional(volume.ConfigMap.Optional) {
+				continue
+			}
+			var keys []string
+			for _, item := range volume.ConfigMap.Items {
+				keys = append(keys, item.Key)
+			}
+			references = append(references, configMapReference{name: volume.ConfigMap.Name, field: fmt.Sprintf("%s.volumes[%d].configMap", prefix, j), keys: keys,
+				configMapType: "genericComponents.Deployment.Volumes.ConfigMap", component: gc.Name})
+		}
+	}
+
+	return references
+}
+
+// Check the result of fetching a config map referenced by a model, and that it contains the referenced keys
+func checkConfigMapReference(reference configMapReference, configMap *corev1.ConfigMap, err error) []Problem {
+	if k8sErrors.IsNotFound(err) {
+		problem := newFieldProblem(reference.field, problemTypeNotFound, "model references %s \"%s\" for component %s.  This config map must be created in the default namespace before proceeding.", reference.configMapType, reference.name, reference.component)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+	if err != nil {
+		problem := newFieldProblem(reference.field, "", "failed to get referenced config map %s in namespace default: %v", reference.name, err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+
+	for _, key := range reference.keys {
+		_, inData := configMap.Data[key]
+		_, inBinaryData := configMap.BinaryData[key]
+		if !inData && !inBinaryData {
+			problem := newFieldProblem(reference.field, problemTypeInvalid, "model references key \"%s\" of %s \"%s\" for component %s.  This key must be added to the config map in the default namespace before proceeding.", key, reference.configMapType, reference.name, reference.component)
+			zap.S().Errorw(problem.Message)
+			return []Problem{problem}
+		}
+	}
+
+	return nil
+}
+
+// Fetch a config map from the default namespace referenced by a component
+func fetchConfigMap(ctx context.Context, clientsets *Clientsets, configMapName string, compName string) (*corev1.ConfigMap, error) {
+	defer observeLookup(lookupGetConfigMap, time.Now())
+	configMap, err := clientsets.K8sClient.CoreV1().ConfigMaps("default").Get(ctx, configMapName, metav1.GetOptions{})
+	recordTimedOutLookup(ctx, err, fmt.Sprintf("config map %s of component %s", configMapName, compName))
+	return configMap, err
+}
+
+func validateCoherenceClusters(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateCoherenceClusters code")
 
-	for _, cc := range model.Spec.CoherenceClusters {
-		for _, connection := range cc.Connections {
-			message := validateRestConnections(connection.Rest)
+	for i, cc := range model.Spec.CoherenceClusters {
+		for j, connection := range cc.Connections {
+			if problems := validateRestConnections(connection.Rest, fmt.Sprintf("spec.coherenceClusters[%d].connections[%d]", i, j)); len(problems) > 0 {
+				return problems
+			}
+		}
+	}
+
+	var messages []Problem
+	for i, cc := range model.Spec.CoherenceClusters {
+		prefix := fmt.Sprintf("spec.coherenceClusters[%d]", i)
+		messages = append(messages, validateCoherenceConfigFile(cc.CacheConfig, prefix+".cacheConfig")...)
+		messages = append(messages, validateCoherenceConfigFile(cc.PofConfig, prefix+".pofConfig")...)
+		messages = append(messages, validateCoherencePorts(cc, prefix)...)
+		messages = append(messages, validateRestConnectionCollisions(cc.Connections, nil, prefix)...)
+	}
+
+	if len(messages) > 0 {
+		zap.S().Errorw(problemsMessage(messages))
+	}
+	return messages
+}
+
+// Validate the name of a Coherence configuration file.  The file is loaded from the class path of the
+// Coherence cluster so it must be a relative XML file name.
+func validateCoherenceConfigFile(fileName string, field string) []Problem {
+	if fileName == "" {
+		return nil
+	}
+	if s.ContainsAny(fileName, " \t\\") || path.IsAbs(fileName) || !s.HasSuffix(fileName, ".xml") {
+		return []Problem{fieldProblem(field, problemTypeInvalid, fmt.Sprintf("\"%s\": must be a relative path to an XML file, for example coherence-cache-config.xml", fileName))}
+	}
+	return nil
+}
+
+// Validate the ports of a Coherence cluster
+func validateCoherencePorts(cc v1beta1v8o.VerrazzanoCoherenceCluster, prefix string) []Problem {
+	var messages []Problem
+
+	portNames := make(map[string]bool)
+	ports := make(map[int32]bool)
+	for j, port := range cc.Ports {
+		field := fmt.Sprintf("%s.ports[%d]", prefix, j)
+		for _, msg := range k8sValidations.IsValidPortName(port.Name) {
+			messages = append(messages, fieldProblem(field+".name", problemTypeInvalid, fmt.Sprintf("\"%s\": %s", port.Name, msg)))
+		}
+		if portNames[port.Name] {
+			messages = append(messages, fieldProblem(field+".name", problemTypeDuplicate, fmt.Sprintf("\"%s\"", port.Name)))
+		}
+		portNames[port.Name] = true
+
+		if port.Port != 0 {
+			message := validatePort(int(port.Port))
 			if message != "" {
-				return message
+				messages = append(messages, fieldProblem(field+".port", "", message))
+			} else if ports[port.Port] {
+				messages = append(messages, fieldProblem(field+".port", problemTypeDuplicate, fmt.Sprintf("%d", port.Port)))
+			}
+			ports[port.Port] = true
+		}
+
+		if port.Protocol != nil {
+			switch corev1.Protocol(*port.Protocol) {
+			case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+			default:
+				messages = append(messages, fieldProblem(field+".protocol", problemTypeNotSupported, fmt.Sprintf("\"%s\": supported values: \"TCP\", \"UDP\", \"SCTP\"", *port.Protocol)))
 			}
 		}
 	}
-	return ""
+
+	return messages
 }
 
-// Validate that there is only one WebLogic cluster per domain
-func validateSingleWebLogicCluster(model v1beta1v8o.VerrazzanoModel) string {
-	zap.S().Debugw("In validateSingleWebLogicCluster code")
+// Validate the Coherence connections of all components.  The target may be a Coherence cluster of this model or of
+// another model.  The connection address must be the well known address (WKA) service of the target Coherence
+// cluster, optionally qualified with its namespace and domain and followed by a port, otherwise the component silently
+// fails to join the cluster.
+func validateCoherenceConnections(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
+	zap.S().Debugw("In validateCoherenceConnections code")
+
+	clusters := make(map[string]bool)
+	for _, cc := range model.Spec.CoherenceClusters {
+		clusters[cc.Name] = true
+	}
+
+	// The other models are only listed when a target is not a Coherence cluster of this model.  When they can't be
+	// listed the targets aren't checked, only the addresses are.
+	var messages []Problem
+	listed := false
+	var listErr error
+	isCoherenceCluster := func(target string) bool {
+		if clusters[target] || listed {
+			return clusters[target] || listErr != nil
+		}
+		listed = true
+		modelList, err := listModels(ctx, clientsets, "")
+		if err != nil {
+			listErr = err
+			problem := newProblem("failed to list models to check Coherence connection targets: %v", err)
+			zap.S().Errorw(problem.Message)
+			messages = append(messages, problem)
+			return true
+		}
+		for _, other := range modelList.Items {
+			// Skip the model being updated
+			if other.Namespace == model.Namespace && other.Name == model.Name {
+				continue
+			}
+			for _, cc := range other.Spec.CoherenceClusters {
+				clusters[cc.Name] = true
+			}
+		}
+		return clusters[target]
+	}
 
-	var messages []string
-	for _, wd := range model.Spec.WeblogicDomains {
-		if len(wd.DomainCRValues.Clusters) > 1 {
-			message := fmt.Sprintf("More than one WebLogic cluster is not allowed for WebLogic domain %s", wd.Name)
-			zap.S().Errorw(message)
-			messages = append(messages, message)
+	validate := func(connections []v1beta1v8o.VerrazzanoConnections, prefix string) {
+		for j, connection := range connections {
+			for k, coherence := range connection.Coherence {
+				field := fmt.Sprintf("%s.connections[%d].coherence[%d]", prefix, j, k)
+				if !isCoherenceCluster(coherence.Target) {
+					messages = append(messages, fieldProblem(field+".target", problemTypeInvalid, fmt.Sprintf("\"%s\": Coherence cluster does not exist in any model", coherence.Target)))
+					continue
+				}
+				wka := coherence.Target + "-wka"
+				if wellKnownAddressService(coherence.Address) != wka {
+					messages = append(messages, fieldProblem(field+".address", problemTypeInvalid, fmt.Sprintf("\"%s\": must be \"%s\", the well known address of Coherence cluster %s, optionally qualified with its namespace and followed by a port", coherence.Address, wka, coherence.Target)))
+				}
+			}
 		}
 	}
 
+	for i, ha := range model.Spec.HelidonApplications {
+		validate(ha.Connections, fmt.Sprintf("spec.helidonApplications[%d]", i))
+	}
+	for i, wd := range model.Spec.WeblogicDomains {
+		validate(wd.Connections, fmt.Sprintf("spec.weblogicDomains[%d]", i))
+	}
+	for i, gc := range model.Spec.GenericComponents {
+		validate(gc.Connections, fmt.Sprintf("spec.genericComponents[%d]", i))
+	}
+
 	if len(messages) > 0 {
-		return s.Join(messages, "; ")
+		zap.S().Errorw(problemsMessage(messages))
 	}
+	return messages
+}
 
-	return ""
+// Get the service name of a Coherence connection address, which is the first label of its host name.  The host may
+// be qualified with a namespace and domain, e.g. bobbys-coherence-wka.bobby.svc.cluster.local, and followed by a port.
+func wellKnownAddressService(address string) string {
+	host := address
+	if i := s.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	return s.SplitN(host, ".", 2)[0]
+}
+
+// Validate the WebLogic clusters of each domain against the policy
+func validateWebLogicClusters(model v1beta1v8o.VerrazzanoModel, policy Policy) []Problem {
+	zap.S().Debugw("In validateWebLogicClusters code")
+
+	var messages []Problem
+	for i, wd := range model.Spec.WeblogicDomains {
+		clusters := wd.DomainCRValues.Clusters
+		maxClusters := policy.MaxWebLogicClustersPerDomain
+		if maxClusters == 1 && len(clusters) > 1 {
+			messages = append(messages, newFieldProblem(fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.clusters", i), problemTypeForbidden,
+				"More than one WebLogic cluster is not allowed for WebLogic domain %s", wd.Name))
+		} else if maxClusters > 0 && len(clusters) > maxClusters {
+			messages = append(messages, newFieldProblem(fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.clusters", i), problemTypeForbidden, "More than %d WebLogic clusters are not allowed for WebLogic domain %s", maxClusters, wd.Name))
+		}
+
+		clusterNames := make(map[string]bool)
+		for j, cluster := range clusters {
+			field := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.clusters[%d]", i, j)
+
+			// The WebLogic operator derives Kubernetes resource names from the lower case cluster name with
+			// underscores replaced by dashes.
+			if cluster.ClusterName == "" {
+				messages = append(messages, fieldProblem(field+".clusterName", problemTypeRequired, fmt.Sprintf("cluster name is required for WebLogic domain %s", wd.Name)))
+			} else {
+				k8sName := s.ReplaceAll(s.ToLower(cluster.ClusterName), "_", "-")
+				for _, msg := range k8sValidations.IsDNS1123Label(k8sName) {
+					messages = append(messages, fieldProblem(field+".clusterName", problemTypeInvalid, fmt.Sprintf("\"%s\": %s", cluster.ClusterName, msg)))
+				}
+				if clusterNames[k8sName] {
+					messages = append(messages, fieldProblem(field+".clusterName", problemTypeDuplicate, fmt.Sprintf("\"%s\": cluster names must be unique within WebLogic domain %s", cluster.ClusterName, wd.Name)))
+				}
+				clusterNames[k8sName] = true
+			}
+
+			if cluster.Replicas < 0 {
+				messages = append(messages, fieldProblem(field+".replicas", problemTypeInvalid, fmt.Sprintf("%d: must be greater than or equal to 0", cluster.Replicas)))
+			} else if policy.MaxWebLogicClusterReplicas > 0 && cluster.Replicas > policy.MaxWebLogicClusterReplicas {
+				messages = append(messages, fieldProblem(field+".replicas", problemTypeInvalid, fmt.Sprintf("%d: must be less than or equal to %d", cluster.Replicas, policy.MaxWebLogicClusterReplicas)))
+			}
+		}
+	}
+
+	if len(messages) > 0 {
+		zap.S().Errorw(problemsMessage(messages))
+	}
+
+	return messages
 }
 
-func validateWebLogicDomains(model v1beta1v8o.VerrazzanoModel) string {
+func validateWebLogicDomains(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateWebLogicDomains code")
 
-	for _, wd := range model.Spec.WeblogicDomains {
-		for _, connection := range wd.Connections {
-			message := validateRestConnections(connection.Rest)
-			if message != "" {
-				return message
+	var portMessages []Problem
+	for i, wd := range model.Spec.WeblogicDomains {
+		for j, connection := range wd.Connections {
+			if problems := validateRestConnections(connection.Rest, fmt.Sprintf("spec.weblogicDomains[%d].connections[%d]", i, j)); len(problems) > 0 {
+				return problems
 			}
 		}
-		var portMessages []string
-		if wd.AdminPort != 0 {
-			message := validatePort(wd.AdminPort)
-			if message != "" {
-				portMessages = append(portMessages, message)
+		prefix := fmt.Sprintf("spec.weblogicDomains[%d]", i)
+		portMessages = append(portMessages, validateWebLogicDomainPorts(wd, prefix)...)
+		portMessages = append(portMessages, validateRestConnectionCollisions(wd.Connections, declaredEnv(wd.DomainCRValues.ServerPod.Env, prefix+".domainCRValues.serverPod.env"), prefix)...)
+	}
+
+	return portMessages
+}
+
+// Validate the ports of a WebLogic domain.  Each port is checked on its own and then against the other ports
+// used by the servers of the domain.  A port of zero means the default is used.
+func validateWebLogicDomainPorts(wd v1beta1v8o.VerrazzanoWebLogicDomain, prefix string) []Problem {
+	var messages []Problem
+
+	if wd.AdminPort != 0 {
+		message := validatePort(wd.AdminPort)
+		if message != "" {
+			messages = append(messages, fieldProblem(prefix+".adminPort", "", message))
+		}
+	}
+	if wd.T3Port != 0 {
+		message := validatePort(wd.T3Port)
+		if message != "" {
+			messages = append(messages, fieldProblem(prefix+".t3Port", "", message))
+		}
+	}
+
+	// Ports already in use by the servers of the domain, keyed by port number
+	usedPorts := map[int]string{
+		defaultWebLogicManagedServerPort: fmt.Sprintf("the default managed server port %d", defaultWebLogicManagedServerPort),
+	}
+	if wd.AdminPort != 0 {
+		addWebLogicPortUse(usedPorts, wd.AdminPort, prefix+".adminPort", "AdminPort", wd.Name, &messages)
+	} else {
+		usedPorts[defaultWebLogicAdminServerPort] = fmt.Sprintf("the default admin server port %d", defaultWebLogicAdminServerPort)
+	}
+	if wd.T3Port != 0 {
+		if wd.T3Port == wd.AdminPort {
+			problem := fieldProblem(prefix+".t3Port", "", fmt.Sprintf("AdminPort and T3Port in WebLogic domain %s have the same value: %v", wd.Name, wd.AdminPort))
+			zap.S().Errorw(problem.Message)
+			messages = append(messages, problem)
+		} else {
+			addWebLogicPortUse(usedPorts, wd.T3Port, prefix+".t3Port", "T3Port", wd.Name, &messages)
+		}
+	}
+
+	// Sidecar containers run in the same pod as the WebLogic server so they cannot reuse its ports
+	for j, container := range wd.DomainCRValues.ServerPod.Containers {
+		for k, port := range container.Ports {
+			field := fmt.Sprintf("%s.domainCRValues.serverPod.containers[%d].ports[%d].containerPort", prefix, j, k)
+			messages = append(messages, validateWebLogicContainerPort(usedPorts, int(port.ContainerPort), field, wd.Name)...)
+		}
+	}
+	for c, cluster := range wd.DomainCRValues.Clusters {
+		for j, container := range cluster.ServerPod.Containers {
+			for k, port := range container.Ports {
+				field := fmt.Sprintf("%s.domainCRValues.clusters[%d].serverPod.containers[%d].ports[%d].containerPort", prefix, c, j, k)
+				messages = append(messages, validateWebLogicContainerPort(usedPorts, int(port.ContainerPort), field, wd.Name)...)
 			}
 		}
-		if wd.AdminPort != 0 {
-			message := validatePort(wd.T3Port)
-			if message != "" {
-				portMessages = append(portMessages, message)
+	}
+
+	return messages
+}
+
+// Validate the domainCRValues of each WebLogic domain
+func validateWebLogicDomainValues(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
+	zap.S().Debugw("In validateWebLogicDomainValues code")
+
+	var messages []Problem
+	for i, wd := range model.Spec.WeblogicDomains {
+		values := wd.DomainCRValues
+		prefix := fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues", i)
+
+		if values.DomainHome != "" && !path.IsAbs(values.DomainHome) {
+			messages = append(messages, fieldProblem(prefix+".domainHome", problemTypeInvalid, fmt.Sprintf("\"%s\": must be an absolute path", values.DomainHome)))
+		}
+		if values.LogHome != "" && !path.IsAbs(values.LogHome) {
+			messages = append(messages, fieldProblem(prefix+".logHome", problemTypeInvalid, fmt.Sprintf("\"%s\": must be an absolute path", values.LogHome)))
+		}
+		if values.LogHomeEnabled && values.LogHome == "" {
+			messages = append(messages, fieldProblem(prefix+".logHome", problemTypeRequired, "logHome must be set when logHomeEnabled is true"))
+		}
+		if values.Replicas != nil && *values.Replicas < 0 {
+			messages = append(messages, fieldProblem(prefix+".replicas", problemTypeInvalid, fmt.Sprintf("%d: must be greater than or equal to 0", *values.Replicas)))
+		}
+
+		envNames := make(map[string]bool)
+		for j, env := range values.ServerPod.Env {
+			field := fmt.Sprintf("%s.serverPod.env[%d].name", prefix, j)
+			for _, msg := range k8sValidations.IsEnvVarName(env.Name) {
+				messages = append(messages, fieldProblem(field, problemTypeInvalid, fmt.Sprintf("\"%s\": %s", env.Name, msg)))
 			}
+			if envNames[env.Name] {
+				messages = append(messages, fieldProblem(field, problemTypeDuplicate, fmt.Sprintf("\"%s\"", env.Name)))
+			}
+			envNames[env.Name] = true
 		}
+	}
 
-		if wd.AdminPort != 0 && wd.T3Port != 0 && wd.AdminPort == wd.T3Port {
-			message := fmt.Sprintf("AdminPort and T3Port in WebLogic domain %s have the same value: %v", wd.Name, wd.AdminPort)
-			zap.S().Errorw(message)
-			portMessages = append(portMessages, message)
+	messages = append(messages, validateWebLogicDomainUIDs(ctx, model, clientsets)...)
+
+	if len(messages) > 0 {
+		zap.S().Errorw(problemsMessage(messages))
+	}
+
+	return messages
+}
+
+// Validate that the domain UID of each WebLogic domain is unique across all models in the cluster.  The WebLogic
+// operator identifies domains by their UID so two domains with the same UID would collide.
+func validateWebLogicDomainUIDs(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
+	zap.S().Debugw("In validateWebLogicDomainUIDs code")
+
+	if len(model.Spec.WeblogicDomains) == 0 {
+		return nil
+	}
+
+	var messages []Problem
+	// Index of the WebLogic domain using each domain UID
+	domainUIDs := make(map[string]int)
+	for i, wd := range model.Spec.WeblogicDomains {
+		uid := webLogicDomainUID(wd)
+		if other, ok := domainUIDs[uid]; ok {
+			messages = append(messages, fieldProblem(fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.domainUID", i), problemTypeDuplicate,
+				fmt.Sprintf("\"%s\": domain UID is already used by WebLogic domain %s", uid, model.Spec.WeblogicDomains[other].Name)))
+			continue
 		}
+		domainUIDs[uid] = i
+	}
 
-		if len(portMessages) > 0 {
-			return s.Join(portMessages, "; ")
+	modelList, err := listModels(ctx, clientsets, "")
+	if err != nil {
+		problem := newProblem("failed to list models to check WebLogic domain UIDs: %v", err)
+		zap.S().Errorw(problem.Message)
+		return append(messages, problem)
+	}
+	for _, other := range modelList.Items {
+		// Skip the model being updated
+		if other.Namespace == model.Namespace && other.Name == model.Name {
+			continue
+		}
+		for _, wd := range other.Spec.WeblogicDomains {
+			uid := webLogicDomainUID(wd)
+			if i, ok := domainUIDs[uid]; ok {
+				messages = append(messages, newFieldProblem(fmt.Sprintf("spec.weblogicDomains[%d].domainCRValues.domainUID", i), problemTypeDuplicate,
+					"WebLogic domain %s uses domain UID \"%s\" which is already used by WebLogic domain %s in model %s in namespace %s",
+					model.Spec.WeblogicDomains[i].Name, uid, wd.Name, other.Name, other.Namespace))
+			}
 		}
 	}
-	return ""
+
+	return messages
+}
+
+// Get the domain UID of a WebLogic domain, the component name is used when no domain UID is given
+func webLogicDomainUID(wd v1beta1v8o.VerrazzanoWebLogicDomain) string {
+	if wd.DomainCRValues.DomainUID != "" {
+		return wd.DomainCRValues.DomainUID
+	}
+	return wd.Name
+}
+
+// Record the use of a WebLogic domain port, adding a message if the port is already in use
+func addWebLogicPortUse(usedPorts map[int]string, port int, field string, portName string, domainName string, messages *[]Problem) {
+	if use, ok := usedPorts[port]; ok {
+		problem := fieldProblem(field, "", fmt.Sprintf("%s in WebLogic domain %s has the value %v which collides with %s", portName, domainName, port, use))
+		zap.S().Errorw(problem.Message)
+		*messages = append(*messages, problem)
+		return
+	}
+	usedPorts[port] = fmt.Sprintf("%s %v", field, port)
+}
+
+// Validate a serverPod container port of a WebLogic domain
+func validateWebLogicContainerPort(usedPorts map[int]string, port int, field string, domainName string) []Problem {
+	if port == 0 {
+		return nil
+	}
+	message := validatePort(port)
+	if message != "" {
+		return []Problem{fieldProblem(field, "", message)}
+	}
+	if use, ok := usedPorts[port]; ok {
+		problem := fieldProblem(field, "", fmt.Sprintf("serverPod container port %v in WebLogic domain %s collides with %s", port, domainName, use))
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+	return nil
 }
 
-func validateHelidonApplications(model v1beta1v8o.VerrazzanoModel) string {
+func validateHelidonApplications(model v1beta1v8o.VerrazzanoModel) []Problem {
 	zap.S().Debugw("In validateHelidonApplications code")
 
-	for _, ha := range model.Spec.HelidonApplications {
-		for _, connection := range ha.Connections {
-			message := validateRestConnections(connection.Rest)
-			if message != "" {
-				return message
+	for i, ha := range model.Spec.HelidonApplications {
+		for j, connection := range ha.Connections {
+			if problems := validateRestConnections(connection.Rest, fmt.Sprintf("spec.helidonApplications[%d].connections[%d]", i, j)); len(problems) > 0 {
+				return problems
 			}
 		}
-		var portMessages []string
+	}
+
+	var messages []Problem
+	for i, ha := range model.Spec.HelidonApplications {
+		prefix := fmt.Sprintf("spec.helidonApplications[%d]", i)
 		if ha.Port != 0 {
 			message := validatePort(int(ha.Port))
 			if message != "" {
-				portMessages = append(portMessages, message)
+				messages = append(messages, fieldProblem(prefix+".port", "", message))
 			}
 		}
 		if ha.TargetPort != 0 {
 			message := validatePort(int(ha.TargetPort))
 			if message != "" {
-				portMessages = append(portMessages, message)
+				messages = append(messages, fieldProblem(prefix+".targetPort", "", message))
 			}
 		}
-		if len(portMessages) > 0 {
-			return s.Join(portMessages, "; ")
+		messages = append(messages, validateHelidonEnv(ha, prefix)...)
+		messages = append(messages, validateRestConnectionCollisions(ha.Connections, declaredEnv(ha.Env, prefix+".env"), prefix)...)
+	}
+
+	if len(messages) > 0 {
+		zap.S().Errorw(problemsMessage(messages))
+	}
+	return messages
+}
+
+// Validate the env of a Helidon application.  Names must be valid and unique.
+func validateHelidonEnv(ha v1beta1v8o.VerrazzanoHelidon, prefix string) []Problem {
+	var messages []Problem
+	envNames := make(map[string]bool)
+	for j, env := range ha.Env {
+		field := fmt.Sprintf("%s.env[%d].name", prefix, j)
+		for _, msg := range k8sValidations.IsEnvVarName(env.Name) {
+			messages = append(messages, fieldProblem(field, problemTypeInvalid, fmt.Sprintf("\"%s\": %s", env.Name, msg)))
+		}
+		if envNames[env.Name] {
+			messages = append(messages, fieldProblem(field, problemTypeDuplicate, fmt.Sprintf("\"%s\"", env.Name)))
 		}
+		envNames[env.Name] = true
 	}
-	return ""
+
+	return messages
+}
+
+// Validate the ports of the Helidon applications against the other Helidon applications placed into the same
+// namespace of a cluster by the bindings of the model and by the bindings of the other models
+func validateHelidonPlacements(ctx context.Context, model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets) []Problem {
+	zap.S().Debugw("In validateHelidonPlacements code")
+
+	if len(model.Spec.HelidonApplications) == 0 {
+		return nil
+	}
+
+	bindingList, err := listBindings(ctx, clientsets, "")
+	if err != nil {
+		problem := newProblem("failed to list bindings in all namespaces: %v", err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+	var bindings []v1beta1v8o.VerrazzanoBinding
+	for _, binding := range bindingList.Items {
+		if binding.Namespace == model.Namespace && binding.Spec.ModelName == model.Name {
+			bindings = append(bindings, binding)
+		}
+	}
+	if len(bindings) == 0 {
+		return nil
+	}
+
+	return validateHelidonBindingPlacements(ctx, model, bindings, bindingList.Items, clientsets)
+}
+
+// Validate the ports of the Helidon applications of a model placed by some of its bindings against the Helidon
+// applications placed into the same namespace of a cluster by these bindings and by all other bindings
+func validateHelidonBindingPlacements(ctx context.Context, model v1beta1v8o.VerrazzanoModel, bindings []v1beta1v8o.VerrazzanoBinding,
+	allBindings []v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets) []Problem {
+	modelList, err := listModels(ctx, clientsets, "")
+	if err != nil {
+		problem := newProblem("failed to list models to check Helidon application ports: %v", err)
+		zap.S().Errorw(problem.Message)
+		return []Problem{problem}
+	}
+	models := make(map[string]v1beta1v8o.VerrazzanoModel)
+	for _, other := range modelList.Items {
+		models[other.Namespace+"/"+other.Name] = other
+	}
+	// The model being validated replaces its stored version
+	models[model.Namespace+"/"+model.Name] = model
+
+	validated := make(map[string]bool)
+	for _, binding := range bindings {
+		validated[binding.Namespace+"/"+binding.Name] = true
+	}
+	placed := make(map[string][]placedHelidonApp)
+	for _, other := range allBindings {
+		if validated[other.Namespace+"/"+other.Name] {
+			continue
+		}
+		if otherModel, ok := models[other.Namespace+"/"+other.Spec.ModelName]; ok {
+			addPlacedHelidonApps(placed, otherModel, other, otherModel.Name != model.Name || otherModel.Namespace != model.Namespace)
+		}
+	}
+
+	// Each binding of the model is also compared with the bindings of the model validated before it
+	var messages []Problem
+	for _, binding := range bindings {
+		messages = append(messages, validateHelidonPlacementPorts(model, binding, placed)...)
+		addPlacedHelidonApps(placed, model, binding, false)
+	}
+
+	if len(messages) > 0 {
+		zap.S().Errorw(problemsMessage(messages))
+	}
+	return messages
+}
+
+// A Helidon application placed into a namespace of a cluster by a binding
+type placedHelidonApp struct {
+	app v1beta1v8o.VerrazzanoHelidon
+	// Description of the application, used in messages
+	description string
+	binding     string
+}
+
+// Add the Helidon applications of a model placed by a binding, keyed by cluster and namespace.  The model is
+// described when it is not the model being validated.
+func addPlacedHelidonApps(placed map[string][]placedHelidonApp, model v1beta1v8o.VerrazzanoModel, binding v1beta1v8o.VerrazzanoBinding, describeModel bool) {
+	helidonApps := make(map[string]v1beta1v8o.VerrazzanoHelidon)
+	for _, ha := range model.Spec.HelidonApplications {
+		helidonApps[ha.Name] = ha
+	}
+	for _, placement := range binding.Spec.Placement {
+		for _, namespace := range placement.Namespaces {
+			for _, component := range namespace.Components {
+				ha, ok := helidonApps[component.Name]
+				if !ok {
+					continue
+				}
+				description := "Helidon application " + ha.Name
+				if describeModel {
+					description += fmt.Sprintf(" of model %s/%s", model.Namespace, model.Name)
+				}
+				key := placement.Name + "/" + namespace.Name
+				placed[key] = append(placed[key], placedHelidonApp{app: ha, description: description, binding: binding.Name})
+			}
+		}
+	}
+}
+
+// Validate that Helidon applications placed into the same namespace by a binding don't use the same port or
+// target port as each other or as the Helidon applications already placed into the namespace of the cluster.
+// Ports that are not set use the default of the Verrazzano operator and are not compared.
+func validateHelidonPlacementPorts(model v1beta1v8o.VerrazzanoModel, binding v1beta1v8o.VerrazzanoBinding, placed map[string][]placedHelidonApp) []Problem {
+	helidonApps := make(map[string]int)
+	for i, ha := range model.Spec.HelidonApplications {
+		helidonApps[ha.Name] = i
+	}
+
+	var messages []Problem
+	for _, placement := range binding.Spec.Placement {
+		for _, namespace := range placement.Namespaces {
+			ports := make(map[uint]placedHelidonApp)
+			targetPorts := make(map[uint]placedHelidonApp)
+			use := func(app placedHelidonApp) {
+				if _, ok := ports[app.app.Port]; !ok {
+					ports[app.app.Port] = app
+				}
+				if _, ok := targetPorts[app.app.TargetPort]; !ok {
+					targetPorts[app.app.TargetPort] = app
+				}
+			}
+			for _, app := range placed[placement.Name+"/"+namespace.Name] {
+				use(app)
+			}
+			for _, component := range namespace.Components {
+				i, ok := helidonApps[component.Name]
+				if !ok {
+					continue
+				}
+				ha := model.Spec.HelidonApplications[i]
+				if other, ok := ports[ha.Port]; ok && ha.Port != 0 {
+					messages = append(messages, fieldProblem(fmt.Sprintf("spec.helidonApplications[%d].port", i), problemTypeDuplicate,
+						fmt.Sprintf("%d: Helidon application %s uses the same port as %s in namespace %s of cluster %s in binding %s", ha.Port, ha.Name, other.description, namespace.Name, placement.Name, other.binding)))
+				}
+				if other, ok := targetPorts[ha.TargetPort]; ok && ha.TargetPort != 0 {
+					messages = append(messages, fieldProblem(fmt.Sprintf("spec.helidonApplications[%d].targetPort", i), problemTypeDuplicate,
+						fmt.Sprintf("%d: Helidon application %s uses the same target port as %s in namespace %s of cluster %s in binding %s", ha.TargetPort, ha.Name, other.description, namespace.Name, placement.Name, other.binding)))
+				}
+				use(placedHelidonApp{app: ha, description: "Helidon application " + ha.Name, binding: binding.Name})
+			}
+		}
+	}
+
+	return messages
 }
 
-func validateRestConnections(restConnections []v1beta1v8o.VerrazzanoRestConnection) string {
-	for _, rc := range restConnections {
+// Validate the environment variables of REST connections, the prefix is the path of the connections
+func validateRestConnections(restConnections []v1beta1v8o.VerrazzanoRestConnection, prefix string) []Problem {
+	for k, rc := range restConnections {
+		field := fmt.Sprintf("%s.rest[%d]", prefix, k)
 		errMessages := k8sValidations.IsEnvVarName(rc.EnvironmentVariableForHost)
 		if len(errMessages) > 0 {
 			errMessages = append(errMessages, fmt.Sprintf("Invalid variable name: %s", rc.EnvironmentVariableForHost))
-			errors := s.Join(errMessages, ", ")
-			zap.S().Errorw(errors)
-			return errors
+			problem := newFieldProblem(field+".environmentVariableForHost", problemTypeInvalid, "%s", s.Join(errMessages, ", "))
+			zap.S().Errorw(problem.Message)
+			return []Problem{problem}
 		}
 		errMessages = k8sValidations.IsEnvVarName(rc.EnvironmentVariableForPort)
 		if len(errMessages) > 0 {
 			errMessages = append(errMessages, fmt.Sprintf("Invalid variable name: %s", rc.EnvironmentVariableForPort))
-			errors := s.Join(errMessages, ", ")
-			zap.S().Errorw(errors)
-			return errors
+			problem := newFieldProblem(field+".environmentVariableForPort", problemTypeInvalid, "%s", s.Join(errMessages, ", "))
+			zap.S().Errorw(problem.Message)
+			return []Problem{problem}
 		}
 		if rc.EnvironmentVariableForPort == rc.EnvironmentVariableForHost {
-			message := fmt.Sprintf("REST connection for target %s uses the same environment variable for host and port: %s", rc.Target, rc.EnvironmentVariableForHost)
-			zap.S().Errorw(message)
-			return message
+			problem := newFieldProblem(field+".environmentVariableForPort", problemTypeDuplicate, "REST connection for target %s uses the same environment variable for host and port: %s", rc.Target, rc.EnvironmentVariableForHost)
+			zap.S().Errorw(problem.Message)
+			return []Problem{problem}
 		}
 	}
-	return ""
+	return nil
+}
+
+// Validate that the environment variables injected for the REST connections of a component are unique across all of
+// its connections and don't collide with the environment variables declared by the component.  Otherwise one value
+// silently overwrites the other.
+func validateRestConnectionCollisions(connections []v1beta1v8o.VerrazzanoConnections, declared map[string]string, prefix string) []Problem {
+	var messages []Problem
+
+	// Targets of the REST connections keyed by the environment variables injected for them
+	injected := make(map[string]string)
+	check := func(name string, field string, target string) {
+		if other, ok := injected[name]; ok {
+			messages = append(messages, fieldProblem(field, problemTypeDuplicate, fmt.Sprintf("\"%s\": REST connection for target %s uses the same environment variable as REST connection for target %s", name, target, other)))
+			return
+		}
+		injected[name] = target
+		if declaredField, ok := declared[name]; ok {
+			messages = append(messages, fieldProblem(field, problemTypeInvalid, fmt.Sprintf("\"%s\": REST connection for target %s collides with environment variable declared at %s", name, target, declaredField)))
+		}
+	}
+
+	for j, connection := range connections {
+		for k, rc := range connection.Rest {
+			field := fmt.Sprintf("%s.connections[%d].rest[%d]", prefix, j, k)
+			check(rc.EnvironmentVariableForHost, field+".environmentVariableForHost", rc.Target)
+			// The same variable for host and port of one connection is reported by validateRestConnections
+			if rc.EnvironmentVariableForPort != rc.EnvironmentVariableForHost {
+				check(rc.EnvironmentVariableForPort, field+".environmentVariableForPort", rc.Target)
+			}
+		}
+	}
+
+	return messages
+}
+
+// Get the field paths of environment variables keyed by name
+func declaredEnv(env []corev1.EnvVar, prefix string) map[string]string {
+	declared := make(map[string]string)
+	for i, ev := range env {
+		declared[ev.Name] = fmt.Sprintf("%s[%d].name", prefix, i)
+	}
+	return declared
 }
 
 func validatePort(port int) string {
@@ -578,48 +1343,74 @@ func validatePort(port int) string {
 	return ""
 }
 
-func validateGenericComponents(model v1beta1v8o.VerrazzanoModel) string {
+func validateGenericComponents(model v1beta1v8o.VerrazzanoModel) []Problem {
 	// Check GenericComponents' secrets
-	var errorMessages []string
-	for _, gc := range model.Spec.GenericComponents {
-		for _, container := range gc.Deployment.InitContainers {
-			errorMessages = validateContainerPort(container, errorMessages)
+	var errorMessages []Problem
+	for i, gc := range model.Spec.GenericComponents {
+		prefix := fmt.Sprintf("spec.genericComponents[%d]", i)
+		for j, container := range gc.Deployment.InitContainers {
+			errorMessages = validateContainerPort(container, fmt.Sprintf("%s.deployment.initContainers[%d]", prefix, j), errorMessages)
 		}
-		for _, container := range gc.Deployment.Containers {
-			errorMessages = validateContainerPort(container, errorMessages)
+		for j, container := range gc.Deployment.Containers {
+			errorMessages = validateContainerPort(container, fmt.Sprintf("%s.deployment.containers[%d]", prefix, j), errorMessages)
 		}
-		for _, connection := range gc.Connections {
-			message := validateRestConnections(connection.Rest)
-			if message != "" {
-				errorMessages = append(errorMessages, message)
+		for j, connection := range gc.Connections {
+			errorMessages = append(errorMessages, validateRestConnections(connection.Rest, fmt.Sprintf("%s.connections[%d]", prefix, j))...)
+		}
+		declared := make(map[string]string)
+		for j, container := range gc.Deployment.Containers {
+			for name, field := range declaredEnv(container.Env, fmt.Sprintf("%s.deployment.containers[%d].env", prefix, j)) {
+				declared[name] = field
 			}
 		}
+		errorMessages = append(errorMessages, validateRestConnectionCollisions(gc.Connections, declared, prefix)...)
 	}
-	if len(errorMessages) > 0 {
-		return s.Join(errorMessages, "; ")
-	}
-	return ""
+	return errorMessages
 }
 
-func validateContainerEnv(container corev1.Container, secretType, compName string, clientsets *Clientsets) string {
-	for _, ev := range container.Env {
+// Get the references of the environment of a container to secrets, the prefix is the path of the container
+func containerEnvSecretReferences(container corev1.Container, prefix, secretType, compName string) []secretReference {
+	var references []secretReference
+	for k, ev := range container.Env {
 		if ev.ValueFrom != nil && ev.ValueFrom.SecretKeyRef != nil {
-			secName := ev.ValueFrom.SecretKeyRef.Name
-			message := getSecret(clientsets, secName, secretType, compName)
-			if message != "" {
-				return message
-			}
+			references = append(references, secretReference{name: ev.ValueFrom.SecretKeyRef.Name, field: fmt.Sprintf("%s.env[%d].valueFrom.secretKeyRef", prefix, k),
+				secretType: secretType, component: compName})
 		}
 	}
-	return ""
+	return references
+}
+
+// Get the required references of the environment of a container to config maps, the prefix is the path of the
+// container
+func containerConfigMapReferences(container corev1.Container, prefix, configMapType, compName string) []configMapReference {
+	var references []configMapReference
+	for k, ev := range container.Env {
+		if ev.ValueFrom != nil && ev.ValueFrom.ConfigMapKeyRef != nil && !isOptional(ev.ValueFrom.ConfigMapKeyRef.Optional) {
+			ref := ev.ValueFrom.ConfigMapKeyRef
+			references = append(references, configMapReference{name: ref.Name, field: fmt.Sprintf("%s.env[%d].valueFrom.configMapKeyRef", prefix, k),
+				keys: []string{ref.Key}, configMapType: configMapType + ".Env", component: compName})
+		}
+	}
+	for k, ef := range container.EnvFrom {
+		if ef.ConfigMapRef != nil && !isOptional(ef.ConfigMapRef.Optional) {
+			references = append(references, configMapReference{name: ef.ConfigMapRef.Name, field: fmt.Sprintf("%s.envFrom[%d].configMapRef", prefix, k),
+				configMapType: configMapType + ".EnvFrom", component: compName})
+		}
+	}
+	return references
+}
+
+// A reference marked optional does not need to exist
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
 }
 
-func validateContainerPort(container corev1.Container, errorMessages []string) []string {
-	for _, port := range container.Ports {
+func validateContainerPort(container corev1.Container, prefix string, errorMessages []Problem) []Problem {
+	for i, port := range container.Ports {
 		if port.ContainerPort != 0 {
 			message := validatePort(int(port.ContainerPort))
 			if message != "" {
-				errorMessages = append(errorMessages, message)
+				errorMessages = append(errorMessages, fieldProblem(fmt.Sprintf("%s.ports[%d].containerPort", prefix, i), "", message))
 			}
 		}
 	}
@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	vzv1b "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	v8oclientset "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/typed/verrazzano/v1beta1"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -46,7 +48,7 @@ func TestValidateGenericComponents(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			if errorMessage := validateGenericComponents(test.args.model); len(test.expectedErrors) > 0 {
+			if errorMessage := problemsMessage(validateGenericComponents(test.args.model)); len(test.expectedErrors) > 0 {
 				for _, s := range test.expectedErrors {
 					if !strings.Contains(errorMessage, s) {
 						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrors)
@@ -67,8 +69,8 @@ func TestValidateModel(t *testing.T) {
 	binding.Namespace = model.Namespace
 	binding.Name = model.Name + "Binding"
 	secrets := []*corev1.Secret{
-		newSecret("default", "ocr", "hello-ocr"),
-		newSecret("default", "github-packages", "github-packages"),
+		newImagePullSecret("default", "ocr"),
+		newImagePullSecret("default", "github-packages"),
 		newSecret("default", "bobbys-front-end-weblogic-credentials", "hello"),
 		newSecret("default", "bobs-bookstore-weblogic-credentials", "hello"),
 		newSecret("default", "mysql-credentials", "hello")}
@@ -76,6 +78,10 @@ func TestValidateModel(t *testing.T) {
 	model2 := ReadModel("testdata/bobs-books-v2-model.yaml")
 	model2.Spec.GenericComponents[0].Deployment.InitContainers = model2.Spec.GenericComponents[0].Deployment.Containers
 
+	invalidConfigMapModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	invalidConfigMapModel.Spec.GenericComponents[0].Deployment.Volumes[0].ConfigMap.Name = "MySQL_InitDB"
+	invalidConfigMapModel.Spec.WeblogicDomains[1].DomainCRValues.Configuration.OverridesConfigMap = "Bookstore_Overrides"
+
 	tests := []struct {
 		name                    string
 		k8sClient               kubernetes.Interface
@@ -84,8 +90,13 @@ func TestValidateModel(t *testing.T) {
 	}{
 		{
 			name:      "TestValidateModel",
-			k8sClient: fakek8s.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3], secrets[4]),
+			k8sClient: fakek8s.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3], secrets[4], configMapOf("mysql-initdb-config")),
 			model:     model,
+		}, {
+			name:                    "TestValidateModelWithMissingConfigMap",
+			k8sClient:               fakek8s.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3], secrets[4]),
+			model:                   model,
+			expectedErrorSubstrings: []string{"genericComponents.Deployment.Volumes.ConfigMap \"mysql-initdb-config\"", "default"},
 		}, {
 			name:                    "TestValidateModelWithMissingSecret",
 			k8sClient:               fakek8s.NewSimpleClientset(),
@@ -101,17 +112,660 @@ func TestValidateModel(t *testing.T) {
 			k8sClient:               fakek8s.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3]),
 			model:                   model2,
 			expectedErrorSubstrings: []string{"mysql-credentials", "default"},
+		}, {
+			name:      "TestValidateModelWithInvalidConfigMapNames",
+			k8sClient: fakek8s.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3], secrets[4]),
+			model:     invalidConfigMapModel,
+			expectedErrorSubstrings: []string{"spec.genericComponents[0].deployment.volumes[0].configMap.name: Invalid value: \"MySQL_InitDB\"",
+				"spec.weblogicDomains[1].domainCRValues.configuration.overridesConfigMap: Invalid value: \"Bookstore_Overrides\""},
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			clientsets := &Clientsets{V8oClient: NewFakeVzClient(test.model, binding), K8sClient: test.k8sClient}
-			admissionReview := validateModel(*test.model, clientsets)
+			problems := validateModel(context.TODO(), *test.model, clientsets, DefaultPolicy())
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Empty(t, problems)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					errorMessage := problemsMessage(problems)
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateModelConfigMaps tests validation of config maps referenced by a VerrazzanoModel
+// GIVEN a VerrazzanoModel referencing config maps and a fake k8s client
+//  WHEN validateModelConfigMaps is called with the VerrazzanoModel and the Clientsets
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateModelConfigMaps(t *testing.T) {
+	optional := true
+	envModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	envModel.Spec.GenericComponents[0].Deployment.Volumes = nil
+	envModel.Spec.GenericComponents[0].Deployment.Containers[0].Env = append(envModel.Spec.GenericComponents[0].Deployment.Containers[0].Env,
+		corev1.EnvVar{Name: "MYSQL_CONFIG", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "mysql-config"}, Key: "config"}}})
+
+	envFromModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	envFromModel.Spec.GenericComponents[0].Deployment.Volumes = nil
+	envFromModel.Spec.GenericComponents[0].Deployment.InitContainers = []corev1.Container{{Name: "init",
+		EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "init-config"}}}}}}
+
+	optionalModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	optionalModel.Spec.GenericComponents[0].Deployment.Volumes[0].ConfigMap.Optional = &optional
+
+	itemsModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	itemsModel.Spec.GenericComponents[0].Deployment.Volumes[0].ConfigMap.Items = []corev1.KeyToPath{{Key: "init.sql", Path: "init.sql"}}
+
+	weblogicModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	weblogicModel.Spec.GenericComponents = nil
+	weblogicModel.Spec.WeblogicDomains[1].DomainCRValues.Configuration.OverridesConfigMap = "bookstore-overrides"
+
+	configMap := configMapOf("mysql-config")
+	configMap.Data = map[string]string{"config": "value"}
+
+	tests := []struct {
+		name                    string
+		k8sClient               kubernetes.Interface
+		model                   *vzv1b.VerrazzanoModel
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:      "TestValidateEnvConfigMap",
+			k8sClient: fakek8s.NewSimpleClientset(configMap),
+			model:     envModel,
+		}, {
+			name:                    "TestValidateMissingEnvConfigMap",
+			k8sClient:               fakek8s.NewSimpleClientset(),
+			model:                   envModel,
+			expectedErrorSubstrings: []string{"genericComponents.Deployment.Containers.Env \"mysql-config\" for component mysql"},
+		}, {
+			name:                    "TestValidateMissingEnvConfigMapKey",
+			k8sClient:               fakek8s.NewSimpleClientset(configMapOf("mysql-config")),
+			model:                   envModel,
+			expectedErrorSubstrings: []string{"key \"config\"", "\"mysql-config\""},
+		}, {
+			name:                    "TestValidateMissingEnvFromConfigMap",
+			k8sClient:               fakek8s.NewSimpleClientset(),
+			model:                   envFromModel,
+			expectedErrorSubstrings: []string{"genericComponents.Deployment.InitContainers.EnvFrom \"init-config\""},
+		}, {
+			name:      "TestValidateOptionalVolumeConfigMap",
+			k8sClient: fakek8s.NewSimpleClientset(),
+			model:     optionalModel,
+		}, {
+			name:                    "TestValidateMissingVolumeConfigMapKey",
+			k8sClient:               fakek8s.NewSimpleClientset(configMapOf("mysql-initdb-config")),
+			model:                   itemsModel,
+			expectedErrorSubstrings: []string{"key \"init.sql\"", "\"mysql-initdb-config\""},
+		}, {
+			name:                    "TestValidateMissingWebLogicOverridesConfigMap",
+			k8sClient:               fakek8s.NewSimpleClientset(),
+			model:                   weblogicModel,
+			expectedErrorSubstrings: []string{"weblogicDomains.domainCRValues.configuration.overridesConfigMap \"bookstore-overrides\" for component bobs-bookstore"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientsets := &Clientsets{K8sClient: test.k8sClient}
+			errorMessage := problemsMessage(validateModelConfigMaps(context.TODO(), *test.model, clientsets))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateWebLogicDomains tests validation of WebLogic domain ports
+// GIVEN a VerrazzanoModel with WebLogic domain ports
+//  WHEN validateWebLogicDomains is called with the VerrazzanoModel
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateWebLogicDomains(t *testing.T) {
+	tests := []struct {
+		name                    string
+		adminPort               int
+		t3Port                  int
+		containerPort           int32
+		expectedErrorSubstrings []string
+	}{
+		{
+			name: "TestDefaultPorts",
+		}, {
+			name:      "TestNonDefaultPorts",
+			adminPort: 2020,
+			t3Port:    7070,
+		}, {
+			name:                    "TestInvalidT3PortWithoutAdminPort",
+			t3Port:                  70000,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].t3Port: Port 70000 is not valid"},
+		}, {
+			name:                    "TestInvalidAdminPort",
+			adminPort:               -1,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].adminPort: Port -1 is not valid"},
+		}, {
+			name:                    "TestSameAdminPortAndT3Port",
+			adminPort:               2020,
+			t3Port:                  2020,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].t3Port: AdminPort and T3Port in WebLogic domain weblogic-domain have the same value: 2020"},
+		}, {
+			name:                    "TestT3PortCollidesWithDefaultAdminPort",
+			t3Port:                  7001,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].t3Port", "collides with the default admin server port 7001"},
+		}, {
+			name:                    "TestAdminPortCollidesWithDefaultManagedServerPort",
+			adminPort:               8001,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].adminPort", "collides with the default managed server port 8001"},
+		}, {
+			name:                    "TestContainerPortCollidesWithT3Port",
+			t3Port:                  7070,
+			containerPort:           7070,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.serverPod.containers[0].ports[0].containerPort", "collides with spec.weblogicDomains[0].t3Port 7070"},
+		}, {
+			name:                    "TestInvalidContainerPort",
+			containerPort:           -1,
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.serverPod.containers[0].ports[0].containerPort: Port -1 is not valid"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := vzv1b.VerrazzanoWebLogicDomain{Name: "weblogic-domain", AdminPort: test.adminPort, T3Port: test.t3Port}
+			if test.containerPort != 0 {
+				domain.DomainCRValues.ServerPod.Containers = []corev1.Container{{Name: "sidecar", Ports: []corev1.ContainerPort{{ContainerPort: test.containerPort}}}}
+			}
+			model := vzv1b.VerrazzanoModel{Spec: vzv1b.VerrazzanoModelSpec{WeblogicDomains: []vzv1b.VerrazzanoWebLogicDomain{domain}}}
+			errorMessage := problemsMessage(validateWebLogicDomains(model))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateWebLogicClusters tests validation of the WebLogic clusters of a domain
+// GIVEN a VerrazzanoModel with WebLogic clusters and a policy
+//  WHEN validateWebLogicClusters is called with the VerrazzanoModel and the policy
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateWebLogicClusters(t *testing.T) {
+	model := ReadModel("testdata/domain-with-multiple-clusters-model.yaml")
+
+	duplicateModel := ReadModel("testdata/domain-with-multiple-clusters-model.yaml")
+	duplicateModel.Spec.WeblogicDomains[0].DomainCRValues.Clusters[1].ClusterName = "Cluster_1"
+
+	invalidModel := ReadModel("testdata/domain-with-multiple-clusters-model.yaml")
+	invalidModel.Spec.WeblogicDomains[0].DomainCRValues.Clusters[0].ClusterName = "cluster.1"
+	invalidModel.Spec.WeblogicDomains[0].DomainCRValues.Clusters[1].Replicas = -1
+
+	replicasModel := ReadModel("testdata/domain-with-multiple-clusters-model.yaml")
+	replicasModel.Spec.WeblogicDomains[0].DomainCRValues.Clusters[1].Replicas = 11
+
+	tests := []struct {
+		name                    string
+		model                   *vzv1b.VerrazzanoModel
+		policy                  Policy
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:                    "TestDefaultPolicy",
+			model:                   model,
+			policy:                  *DefaultPolicy(),
+			expectedErrorSubstrings: []string{"More than one WebLogic cluster is not allowed for WebLogic domain weblogic-domain"},
+		}, {
+			name:   "TestMultipleClustersAllowed",
+			model:  model,
+			policy: Policy{MaxWebLogicClustersPerDomain: 2},
+		}, {
+			name:   "TestNoClusterLimit",
+			model:  model,
+			policy: Policy{},
+		}, {
+			name:                    "TestDuplicateClusterNames",
+			model:                   duplicateModel,
+			policy:                  Policy{},
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.clusters[1].clusterName: Duplicate value: \"Cluster_1\""},
+		}, {
+			name:   "TestInvalidClusterNameAndReplicas",
+			model:  invalidModel,
+			policy: Policy{},
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.clusters[0].clusterName: Invalid value: \"cluster.1\"",
+				"spec.weblogicDomains[0].domainCRValues.clusters[1].replicas: Invalid value: -1"},
+		}, {
+			name:                    "TestClusterReplicasAboveLimit",
+			model:                   replicasModel,
+			policy:                  Policy{MaxWebLogicClusterReplicas: 10},
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.clusters[1].replicas: Invalid value: 11: must be less than or equal to 10"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessage := problemsMessage(validateWebLogicClusters(*test.model, test.policy))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateWebLogicDomainValues tests validation of the domainCRValues of WebLogic domains
+// GIVEN a VerrazzanoModel and a fake Verrazzano client with existing models
+//  WHEN validateWebLogicDomainValues is called with the VerrazzanoModel and the Clientsets
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateWebLogicDomainValues(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+
+	pathsModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	pathsModel.Spec.WeblogicDomains[0].DomainCRValues.DomainHome = "domains/bobbys-front-end"
+	pathsModel.Spec.WeblogicDomains[1].DomainCRValues.LogHome = ""
+	pathsModel.Spec.WeblogicDomains[1].DomainCRValues.LogHomeEnabled = true
+
+	replicas := int32(-1)
+	envModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	envModel.Spec.WeblogicDomains[0].DomainCRValues.Replicas = &replicas
+	envModel.Spec.WeblogicDomains[0].DomainCRValues.ServerPod.Env = append(envModel.Spec.WeblogicDomains[0].DomainCRValues.ServerPod.Env,
+		corev1.EnvVar{Name: "WL_HOME", Value: "/u01"}, corev1.EnvVar{Name: "1BAD", Value: "bad"})
+
+	duplicateModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	duplicateModel.Spec.WeblogicDomains[1].DomainCRValues.DomainUID = "bobbys-front-end"
+
+	otherModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	otherModel.Name = "other-model"
+	otherModel.Namespace = "other"
+	otherModel.Spec.WeblogicDomains = otherModel.Spec.WeblogicDomains[1:]
+
+	tests := []struct {
+		name                    string
+		model                   *vzv1b.VerrazzanoModel
+		v8oClient               v8oclientset.VerrazzanoV1beta1Interface
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:      "TestValidDomainValues",
+			model:     model,
+			v8oClient: NewFakeVzClient(model),
+		}, {
+			name:      "TestInvalidPaths",
+			model:     pathsModel,
+			v8oClient: NewFakeVzClient(),
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.domainHome: Invalid value: \"domains/bobbys-front-end\": must be an absolute path",
+				"spec.weblogicDomains[1].domainCRValues.logHome: Required value"},
+		}, {
+			name:      "TestInvalidEnvAndReplicas",
+			model:     envModel,
+			v8oClient: NewFakeVzClient(),
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].domainCRValues.replicas: Invalid value: -1",
+				"spec.weblogicDomains[0].domainCRValues.serverPod.env[4].name: Duplicate value: \"WL_HOME\"",
+				"spec.weblogicDomains[0].domainCRValues.serverPod.env[5].name: Invalid value: \"1BAD\""},
+		}, {
+			name:                    "TestDuplicateDomainUIDInModel",
+			model:                   duplicateModel,
+			v8oClient:               NewFakeVzClient(),
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[1].domainCRValues.domainUID: Duplicate value: \"bobbys-front-end\""},
+		}, {
+			name:                    "TestDuplicateDomainUIDInOtherModel",
+			model:                   model,
+			v8oClient:               NewFakeVzClient(model, otherModel),
+			expectedErrorSubstrings: []string{"domain UID \"bobs-bookstore\" which is already used by WebLogic domain bobs-bookstore in model other-model in namespace other"},
+		}, {
+			name:                    "TestListModelsError",
+			model:                   model,
+			v8oClient:               MockError(NewFakeVzClient(), "list", "verrazzanomodels", &vzv1b.VerrazzanoModelList{}),
+			expectedErrorSubstrings: []string{"failed to list models to check WebLogic domain UIDs"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientsets := &Clientsets{V8oClient: test.v8oClient, K8sClient: fakek8s.NewSimpleClientset()}
+			errorMessage := problemsMessage(validateWebLogicDomainValues(context.TODO(), *test.model, clientsets))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateCoherenceClusters tests validation of Coherence clusters
+// GIVEN a VerrazzanoModel with Coherence clusters
+//  WHEN validateCoherenceClusters is called with the VerrazzanoModel
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateCoherenceClusters(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+
+	configModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	configModel.Spec.CoherenceClusters[0].CacheConfig = "/config/bobbys-cache-config.xml"
+	configModel.Spec.CoherenceClusters[1].PofConfig = "books-pof-config"
+
+	protocol := "HTTP"
+	portsModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	portsModel.Spec.CoherenceClusters[0].Ports = append(portsModel.Spec.CoherenceClusters[0].Ports, portsModel.Spec.CoherenceClusters[0].Ports[0])
+	portsModel.Spec.CoherenceClusters[1].Ports[0].Port = 70000
+	portsModel.Spec.CoherenceClusters[1].Ports[0].Protocol = &protocol
+
+	tests := []struct {
+		name                    string
+		model                   *vzv1b.VerrazzanoModel
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:  "TestValidCoherenceClusters",
+			model: model,
+		}, {
+			name:  "TestInvalidConfigFiles",
+			model: configModel,
+			expectedErrorSubstrings: []string{"spec.coherenceClusters[0].cacheConfig: Invalid value: \"/config/bobbys-cache-config.xml\"",
+				"spec.coherenceClusters[1].pofConfig: Invalid value: \"books-pof-config\""},
+		}, {
+			name:  "TestInvalidPorts",
+			model: portsModel,
+			expectedErrorSubstrings: []string{"spec.coherenceClusters[0].ports[1].name: Duplicate value: \"extend\"",
+				"spec.coherenceClusters[0].ports[1].port: Duplicate value: 9000",
+				"spec.coherenceClusters[1].ports[0].port: Port 70000 is not valid",
+				"spec.coherenceClusters[1].ports[0].protocol: Unsupported value: \"HTTP\""},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessage := problemsMessage(validateCoherenceClusters(*test.model))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateCoherenceConnections tests validation of the Coherence connections of a VerrazzanoModel
+// GIVEN a VerrazzanoModel with Coherence connections and a fake Verrazzano client
+//  WHEN validateCoherenceConnections is called with the VerrazzanoModel and the Clientsets
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateCoherenceConnections(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+
+	addressModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	addressModel.Spec.HelidonApplications[0].Connections[0].Coherence[0].Address = "roberts-coherence-wka"
+	addressModel.Spec.HelidonApplications[1].Connections[1].Coherence[0].Target = "missing-coherence"
+
+	fqdnModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	fqdnModel.Spec.HelidonApplications[0].Connections[0].Coherence[0].Address = "bobbys-coherence-wka.bobby.svc.cluster.local"
+	fqdnModel.Spec.HelidonApplications[1].Connections[1].Coherence[0].Address = "roberts-coherence-wka.robert:7574"
+
+	// The Coherence clusters are defined by another model
+	cacheModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	cacheModel.Name = "bobs-books-cache"
+	cacheModel.Spec.HelidonApplications = nil
+	cacheModel.Spec.WeblogicDomains = nil
+	cacheModel.Spec.GenericComponents = nil
+	crossModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	crossModel.Spec.CoherenceClusters = nil
+
+	tests := []struct {
+		name                    string
+		model                   *vzv1b.VerrazzanoModel
+		v8oClient               *FakeVzClient
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:      "TestValidCoherenceConnections",
+			model:     model,
+			v8oClient: NewFakeVzClient(),
+		}, {
+			name:      "TestFullyQualifiedWellKnownAddress",
+			model:     fqdnModel,
+			v8oClient: NewFakeVzClient(),
+		}, {
+			name:      "TestCoherenceClusterOfAnotherModel",
+			model:     crossModel,
+			v8oClient: NewFakeVzClient(cacheModel),
+		}, {
+			name:                    "TestCoherenceClusterWithoutModel",
+			model:                   crossModel,
+			v8oClient:               NewFakeVzClient(),
+			expectedErrorSubstrings: []string{"spec.helidonApplications[0].connections[0].coherence[0].target: Invalid value: \"bobbys-coherence\": Coherence cluster does not exist in any model"},
+		}, {
+			name:                    "TestCoherenceClusterWithListError",
+			model:                   crossModel,
+			v8oClient:               MockError(NewFakeVzClient(), "list", "verrazzanomodels", &vzv1b.VerrazzanoModelList{}),
+			expectedErrorSubstrings: []string{"failed to list models to check Coherence connection targets: Error list verrazzanomodels"},
+		}, {
+			name:      "TestInvalidConnectionAddress",
+			model:     addressModel,
+			v8oClient: NewFakeVzClient(),
+			expectedErrorSubstrings: []string{"spec.helidonApplications[0].connections[0].coherence[0].address: Invalid value: \"roberts-coherence-wka\": must be \"bobbys-coherence-wka\"",
+				"spec.helidonApplications[1].connections[1].coherence[0].target: Invalid value: \"missing-coherence\""},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessage := problemsMessage(validateCoherenceConnections(context.TODO(), *test.model, &Clientsets{V8oClient: test.v8oClient}))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateCoherenceImagePullSecretType tests validation of the type of Coherence image pull secrets
+// GIVEN a VerrazzanoModel and a fake k8s client with an opaque image pull secret
+//  WHEN validateModelSecrets is called with the VerrazzanoModel and the Clientsets
+//  THEN the validation should report the type of the secret
+func TestValidateCoherenceImagePullSecretType(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	clientsets := &Clientsets{K8sClient: fakek8s.NewSimpleClientset(newImagePullSecret("default", "ocr"), newSecret("default", "github-packages", "github-packages"))}
+	errorMessage := problemsMessage(validateModelSecrets(context.TODO(), *model, clientsets))
+	assert.Contains(t, errorMessage, "coherenceClusters.imagePullSecret \"github-packages\" for component bobbys-coherence which has type Opaque")
+}
+
+// TestValidateHelidonApplications tests validation of Helidon applications
+// GIVEN a VerrazzanoModel with Helidon applications
+//  WHEN validateHelidonApplications is called with the VerrazzanoModel
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateHelidonApplications(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+
+	envModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	envModel.Spec.HelidonApplications[0].Env = append(envModel.Spec.HelidonApplications[0].Env,
+		corev1.EnvVar{Name: "COH_POF_CONFIG", Value: "pof.xml"},
+		corev1.EnvVar{Name: "BAD=NAME", Value: "bad"},
+		corev1.EnvVar{Name: "BACKEND_PORT", Value: "8080"})
+
+	tests := []struct {
+		name                    string
+		model                   *vzv1b.VerrazzanoModel
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:  "TestValidHelidonApplications",
+			model: model,
+		}, {
+			name:  "TestInvalidEnv",
+			model: envModel,
+			expectedErrorSubstrings: []string{"spec.helidonApplications[0].env[2].name: Duplicate value: \"COH_POF_CONFIG\"",
+				"spec.helidonApplications[0].env[3].name: Invalid value: \"BAD=NAME\"",
+				"spec.helidonApplications[0].connections[1].rest[0].environmentVariableForPort: Invalid value: \"BACKEND_PORT\": REST connection for target bobs-bookstore collides with environment variable declared at spec.helidonApplications[0].env[4].name"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessage := problemsMessage(validateHelidonApplications(*test.model))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateHelidonPlacements tests validation of Helidon application ports across a placement namespace
+// GIVEN a VerrazzanoModel and a fake Verrazzano client with bindings for the model and for other models
+//  WHEN validateHelidonPlacements is called with the VerrazzanoModel and the Clientsets
+//  THEN the validation should produce a result message containing the expected substrings
+func TestValidateHelidonPlacements(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	model.Spec.HelidonApplications[0].Port = 8080
+	model.Spec.HelidonApplications[1].Port = 8080
+	model.Spec.HelidonApplications[1].TargetPort = 9090
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+
+	samePlacement := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	samePlacement.Spec.Placement[0].Namespaces[0].Components = append(samePlacement.Spec.Placement[0].Namespaces[0].Components,
+		samePlacement.Spec.Placement[0].Namespaces[1].Components[0])
+	samePlacement.Spec.Placement[0].Namespaces[1].Components = samePlacement.Spec.Placement[0].Namespaces[1].Components[1:]
+
+	otherModelBinding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	otherModelBinding.Name = "other-binding"
+	otherModelBinding.Spec.ModelName = "other-model"
+	otherModelBinding.Spec.Placement = samePlacement.Spec.Placement
+
+	// Another model places a Helidon application using the same port into namespace bobby
+	otherModel := &vzv1b.VerrazzanoModel{Spec: vzv1b.VerrazzanoModelSpec{
+		HelidonApplications: []vzv1b.VerrazzanoHelidon{{Name: "other-helidon-application", Port: 8080}}}}
+	otherModel.Namespace = "default"
+	otherModel.Name = "other-model"
+	otherBinding := &vzv1b.VerrazzanoBinding{Spec: vzv1b.VerrazzanoBindingSpec{ModelName: "other-model",
+		Placement: []vzv1b.VerrazzanoPlacement{{Name: "local", Namespaces: []vzv1b.KubernetesNamespace{
+			{Name: "bobby", Components: []vzv1b.BindingComponent{{Name: "other-helidon-application"}}}}}}}}
+	otherBinding.Namespace = "default"
+	otherBinding.Name = "other-binding"
+	otherClusterBinding := otherBinding.DeepCopy()
+	otherClusterBinding.Spec.Placement[0].Name = "remote"
+
+	tests := []struct {
+		name                    string
+		v8oClient               v8oclientset.VerrazzanoV1beta1Interface
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:      "TestDifferentNamespaces",
+			v8oClient: NewFakeVzClient(model, binding),
+		}, {
+			name:      "TestBindingForOtherModel",
+			v8oClient: NewFakeVzClient(model, otherModelBinding),
+		}, {
+			name:                    "TestSameNamespace",
+			v8oClient:               NewFakeVzClient(model, samePlacement),
+			expectedErrorSubstrings: []string{"spec.helidonApplications[1].port: Duplicate value: 8080: Helidon application roberts-helidon-stock-application uses the same port as Helidon application bobbys-helidon-stock-application in namespace bobby of cluster local in binding bobs-books-binding"},
+		}, {
+			name:                    "TestOtherModelSameNamespace",
+			v8oClient:               NewFakeVzClient(model, binding, otherModel, otherBinding),
+			expectedErrorSubstrings: []string{"spec.helidonApplications[0].port: Duplicate value: 8080: Helidon application bobbys-helidon-stock-application uses the same port as Helidon application other-helidon-application of model default/other-model in namespace bobby of cluster local in binding other-binding"},
+		}, {
+			name:      "TestOtherModelOtherCluster",
+			v8oClient: NewFakeVzClient(model, binding, otherModel, otherClusterBinding),
+		}, {
+			name:                    "TestListBindingsError",
+			v8oClient:               MockError(NewFakeVzClient(model), "list", "verrazzanobindings", &vzv1b.VerrazzanoBindingList{}),
+			expectedErrorSubstrings: []string{"failed to list bindings in all namespaces"},
+		}, {
+			name:                    "TestListModelsError",
+			v8oClient:               MockError(NewFakeVzClient(model, binding), "list", "verrazzanomodels", &vzv1b.VerrazzanoModelList{}),
+			expectedErrorSubstrings: []string{"failed to list models to check Helidon application ports"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientsets := &Clientsets{V8oClient: test.v8oClient, K8sClient: fakek8s.NewSimpleClientset()}
+			errorMessage := problemsMessage(validateHelidonPlacements(context.TODO(), *model, clientsets))
+			if len(test.expectedErrorSubstrings) == 0 {
+				assert.Equal(t, "", errorMessage)
+			} else {
+				for _, s := range test.expectedErrorSubstrings {
+					if !strings.Contains(errorMessage, s) {
+						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestValidateRestConnectionCollisions tests validation of environment variables injected for REST connections
+// GIVEN a VerrazzanoModel with components that have REST connections
+//  WHEN validateModel is called with the VerrazzanoModel
+//  THEN the validation should report environment variables reused across the connections of a component
+func TestValidateRestConnectionCollisions(t *testing.T) {
+	helidonModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	helidonModel.Spec.HelidonApplications[0].Connections = append(helidonModel.Spec.HelidonApplications[0].Connections,
+		vzv1b.VerrazzanoConnections{Rest: []vzv1b.VerrazzanoRestConnection{{Target: "bobbys-front-end", EnvironmentVariableForHost: "BACKEND_HOSTNAME", EnvironmentVariableForPort: "FRONT_END_PORT"}}})
+
+	weblogicModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	weblogicModel.Spec.WeblogicDomains[0].Connections[1].Rest[0].EnvironmentVariableForHost = "WL_HOME"
+
+	genericModel := ReadModel("testdata/bobs-books-v2-model.yaml")
+	genericModel.Spec.GenericComponents[0].Connections = []vzv1b.VerrazzanoConnections{{Rest: []vzv1b.VerrazzanoRestConnection{
+		{Target: "bobs-bookstore", EnvironmentVariableForHost: "BOOKSTORE_HOST", EnvironmentVariableForPort: "BOOKSTORE_PORT"},
+		{Target: "bobbys-front-end", EnvironmentVariableForHost: "FRONT_END_HOST", EnvironmentVariableForPort: "MYSQL_DATABASE"}}}}
+
+	tests := []struct {
+		name                    string
+		validate                func() []Problem
+		expectedErrorSubstrings []string
+	}{
+		{
+			name:                    "TestHelidonConnections",
+			validate:                func() []Problem { return validateHelidonApplications(*helidonModel) },
+			expectedErrorSubstrings: []string{"spec.helidonApplications[0].connections[2].rest[0].environmentVariableForHost: Duplicate value: \"BACKEND_HOSTNAME\": REST connection for target bobbys-front-end uses the same environment variable as REST connection for target bobs-bookstore"},
+		}, {
+			name:                    "TestWebLogicServerPodEnv",
+			validate:                func() []Problem { return validateWebLogicDomains(*weblogicModel) },
+			expectedErrorSubstrings: []string{"spec.weblogicDomains[0].connections[1].rest[0].environmentVariableForHost: Invalid value: \"WL_HOME\"", "spec.weblogicDomains[0].domainCRValues.serverPod.env[2].name"},
+		}, {
+			name:                    "TestGenericContainerEnv",
+			validate:                func() []Problem { return validateGenericComponents(*genericModel) },
+			expectedErrorSubstrings: []string{"spec.genericComponents[0].connections[0].rest[1].environmentVariableForPort: Invalid value: \"MYSQL_DATABASE\"", "spec.genericComponents[0].deployment.containers[0].env[4].name"},
+		}, {
+			name:     "TestDistinctConnections",
+			validate: func() []Problem { return validateCoherenceClusters(*ReadModel("testdata/bobs-books-v2-model.yaml")) },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errorMessage := problemsMessage(test.validate())
 			if len(test.expectedErrorSubstrings) == 0 {
-				assert.Nil(t, admissionReview.Response)
+				assert.Equal(t, "", errorMessage)
 			} else {
 				for _, s := range test.expectedErrorSubstrings {
-					errorMessage := admissionReview.Response.Result.Message
 					if !strings.Contains(errorMessage, s) {
 						t.Errorf("Error %v should contain %v", errorMessage, test.expectedErrorSubstrings)
 					}
@@ -0,0 +1,155 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+// Policy contains the configurable limits enforced by the validations.  Values can be overridden
+// for the resources of a namespace.
+type Policy struct {
+	// Maximum number of WebLogic clusters allowed in a WebLogic domain, zero means no limit
+	MaxWebLogicClustersPerDomain int `yaml:"maxWebLogicClustersPerDomain"`
+
+	// Maximum number of replicas allowed for a WebLogic cluster, zero means no limit
+	MaxWebLogicClusterReplicas int `yaml:"maxWebLogicClusterReplicas"`
+
+	// Minimum number of replicas allowed for a WebLogic, Coherence or Helidon binding, zero means no limit
+	MinBindingReplicas int `yaml:"minBindingReplicas"`
+
+	// Maximum number of replicas allowed for a WebLogic, Coherence or Helidon binding, zero means no limit
+	MaxBindingReplicas int `yaml:"maxBindingReplicas"`
+
+	// Maximum number of replicas the bindings of all namespaces can place on one VerrazzanoManagedCluster, zero
+	// means no limit.  This value can't be overridden for a namespace.
+	MaxManagedClusterReplicas int `yaml:"maxManagedClusterReplicas"`
+
+	// Allow database binding URLs to reference services in the cluster
+	DatabaseHostsInCluster bool `yaml:"databaseHostsInCluster"`
+
+	// Domains of the hosts database binding URLs can reference.  When neither this nor DatabaseHostsInCluster
+	// is set, any database host is allowed.
+	AllowedDatabaseDomains []string `yaml:"allowedDatabaseDomains,omitempty"`
+
+	// Mode of the rules without a mode in RuleModes, enforce if not set
+	DefaultRuleMode RuleMode `yaml:"defaultRuleMode,omitempty"`
+
+	// Modes of the rules keyed by rule ID, like binding-replicas.  Rules that are always enforced can't have another
+	// mode.
+	RuleModes map[string]RuleMode `yaml:"ruleModes,omitempty"`
+
+	// Policy overrides keyed by namespace name
+	Namespaces map[string]NamespacePolicy `yaml:"namespaces,omitempty"`
+}
+
+// NamespacePolicy contains the policy values overridden for a namespace.  Unset values are taken from the
+// global policy.
+type NamespacePolicy struct {
+	MaxWebLogicClustersPerDomain *int                `yaml:"maxWebLogicClustersPerDomain,omitempty"`
+	MaxWebLogicClusterReplicas   *int                `yaml:"maxWebLogicClusterReplicas,omitempty"`
+	MinBindingReplicas           *int                `yaml:"minBindingReplicas,omitempty"`
+	MaxBindingReplicas           *int                `yaml:"maxBindingReplicas,omitempty"`
+	DatabaseHostsInCluster       *bool               `yaml:"databaseHostsInCluster,omitempty"`
+	AllowedDatabaseDomains       []string            `yaml:"allowedDatabaseDomains,omitempty"`
+	DefaultRuleMode              RuleMode            `yaml:"defaultRuleMode,omitempty"`
+	RuleModes                    map[string]RuleMode `yaml:"ruleModes,omitempty"`
+}
+
+// DefaultPolicy returns the policy used when no policy file is given
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxWebLogicClustersPerDomain: 1,
+	}
+}
+
+// LoadPolicy reads a policy from a YAML file.  Values missing from the file keep their defaults.
+func LoadPolicy(path string) (*Policy, error) {
+	policy := DefaultPolicy()
+	if path == "" {
+		return policy, nil
+	}
+
+	zap.S().Infof("Loading validation policy from %s", path)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file %s: %v", path, err)
+	}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %v", path, err)
+	}
+	if err := policy.validateRuleModes(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %v", path, err)
+	}
+	return policy, nil
+}
+
+// ForNamespace returns the policy that applies to the resources of a namespace
+func (p *Policy) ForNamespace(namespace string) Policy {
+	if p == nil {
+		return *DefaultPolicy()
+	}
+	effective := *p
+	effective.Namespaces = nil
+	override, ok := p.Namespaces[namespace]
+	if !ok {
+		return effective
+	}
+	if override.MaxWebLogicClustersPerDomain != nil {
+		effective.MaxWebLogicClustersPerDomain = *override.MaxWebLogicClustersPerDomain
+	}
+	if override.MaxWebLogicClusterReplicas != nil {
+		effective.MaxWebLogicClusterReplicas = *override.MaxWebLogicClusterReplicas
+	}
+	if override.MinBindingReplicas != nil {
+		effective.MinBindingReplicas = *override.MinBindingReplicas
+	}
+	if override.MaxBindingReplicas != nil {
+		effective.MaxBindingReplicas = *override.MaxBindingReplicas
+	}
+	if override.DatabaseHostsInCluster != nil {
+		effective.DatabaseHostsInCluster = *override.DatabaseHostsInCluster
+	}
+	if override.AllowedDatabaseDomains != nil {
+		effective.AllowedDatabaseDomains = override.AllowedDatabaseDomains
+	}
+	if override.DefaultRuleMode != "" {
+		effective.DefaultRuleMode = override.DefaultRuleMode
+	}
+	effective.RuleModes = mergeRuleModes(p.RuleModes, override.RuleModes)
+	return effective
+}
+
+// Check the rule modes of a policy and of its namespace overrides
+func (p *Policy) validateRuleModes() error {
+	if err := checkRuleModes(p.DefaultRuleMode, p.RuleModes); err != nil {
+		return err
+	}
+	for namespace, override := range p.Namespaces {
+		if err := checkRuleModes(override.DefaultRuleMode, override.RuleModes); err != nil {
+			return fmt.Errorf("%v in namespace %s", err, namespace)
+		}
+	}
+	return nil
+}
+
+// Check a default rule mode, which may be empty, and the modes of rules
+func checkRuleModes(defaultMode RuleMode, modes map[string]RuleMode) error {
+	if defaultMode != "" && !defaultMode.valid() {
+		return fmt.Errorf("invalid default rule mode %s, must be one of %s", defaultMode, ruleModeNames())
+	}
+	for rule, mode := range modes {
+		if !mode.valid() {
+			return fmt.Errorf("invalid mode %s for rule %s, must be one of %s", mode, rule, ruleModeNames())
+		}
+		if mode != RuleModeEnforce && isProtectedRule(rule) {
+			return fmt.Errorf("invalid mode %s for rule %s, the rule is always enforced", mode, rule)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoadPolicy tests loading of the validation policy
+// GIVEN a policy file
+//  WHEN LoadPolicy is called with the path of the file
+//  THEN the policy should contain the values from the file and defaults for the missing values
+func TestLoadPolicy(t *testing.T) {
+	file, err := ioutil.TempFile("", "policy-*.yaml")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`
+maxWebLogicClusterReplicas: 10
+namespaces:
+  default:
+    maxWebLogicClustersPerDomain: 3
+`)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	policy, err := LoadPolicy(file.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, policy.MaxWebLogicClustersPerDomain)
+	assert.Equal(t, 10, policy.MaxWebLogicClusterReplicas)
+
+	policy, err = LoadPolicy("")
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultPolicy(), policy)
+
+	_, err = LoadPolicy(file.Name() + "-missing")
+	assert.NotNil(t, err)
+}
+
+// TestLoadPolicyWithUnknownField tests loading of a policy file containing an unknown field
+// GIVEN a policy file with a misspelled field
+//  WHEN LoadPolicy is called with the path of the file
+//  THEN an error should be returned
+func TestLoadPolicyWithUnknownField(t *testing.T) {
+	file, err := ioutil.TempFile("", "policy-*.yaml")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("maxWebLogicClusters: 3\n")
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	_, err = LoadPolicy(file.Name())
+	assert.NotNil(t, err)
+}
+
+// TestLoadPolicyWithInvalidRuleMode tests loading of a policy file containing an unsupported rule mode
+// GIVEN policy files with an unsupported rule mode, globally or for a namespace, or a protected rule not enforced
+//  WHEN LoadPolicy is called with the path of the file
+//  THEN an error should be returned, and the policy returned for valid modes
+func TestLoadPolicyWithInvalidRuleMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{name: "valid", content: "defaultRuleMode: warn\nruleModes:\n  binding-replicas: off\nnamespaces:\n  bob:\n    defaultRuleMode: audit\n", valid: true},
+		{name: "default", content: "defaultRuleMode: deny\n"},
+		{name: "rule", content: "ruleModes:\n  binding-replicas: warning\n"},
+		{name: "namespace", content: "namespaces:\n  bob:\n    ruleModes:\n      database-bindings: dryrun\n"},
+		{name: "protected", content: "namespaces:\n  bob:\n    ruleModes:\n      model-in-use: off\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file, err := ioutil.TempFile("", "policy-*.yaml")
+			assert.Nil(t, err)
+			defer os.Remove(file.Name())
+			_, err = file.WriteString(test.content)
+			assert.Nil(t, err)
+			assert.Nil(t, file.Close())
+
+			policy, err := LoadPolicy(file.Name())
+			if test.valid {
+				assert.Nil(t, err)
+				assert.Equal(t, RuleModeWarn, policy.DefaultRuleMode)
+				assert.Equal(t, RuleModeAudit, policy.Namespaces["bob"].DefaultRuleMode)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
+
+// TestPolicyForNamespace tests namespace overrides of the validation policy
+// GIVEN a policy with an override for a namespace
+//  WHEN ForNamespace is called
+//  THEN the overridden values should only apply to that namespace
+func TestPolicyForNamespace(t *testing.T) {
+	maxClusters := 3
+	inCluster := false
+	minReplicas := 2
+	policy := &Policy{
+		MaxWebLogicClustersPerDomain: 1,
+		MaxWebLogicClusterReplicas:   5,
+		DatabaseHostsInCluster:       true,
+		MaxManagedClusterReplicas:    20,
+		Namespaces: map[string]NamespacePolicy{
+			"bob": {MaxWebLogicClustersPerDomain: &maxClusters, DatabaseHostsInCluster: &inCluster,
+				AllowedDatabaseDomains: []string{"example.com"}, MinBindingReplicas: &minReplicas},
+		},
+	}
+
+	effective := policy.ForNamespace("bob")
+	assert.Equal(t, 3, effective.MaxWebLogicClustersPerDomain)
+	assert.Equal(t, 5, effective.MaxWebLogicClusterReplicas)
+	assert.False(t, effective.DatabaseHostsInCluster)
+	assert.Equal(t, []string{"example.com"}, effective.AllowedDatabaseDomains)
+	assert.Nil(t, effective.Namespaces)
+	assert.Equal(t, 2, effective.MinBindingReplicas)
+	assert.Equal(t, 20, effective.MaxManagedClusterReplicas)
+
+	effective = policy.ForNamespace("default")
+	assert.Equal(t, 1, effective.MaxWebLogicClustersPerDomain)
+	assert.True(t, effective.DatabaseHostsInCluster)
+	assert.Nil(t, effective.AllowedDatabaseDomains)
+
+	policy = &Policy{
+		DefaultRuleMode: RuleModeWarn,
+		RuleModes:       map[string]RuleMode{ruleDatabaseBindings: RuleModeAudit, ruleBindingReplicas: RuleModeEnforce},
+		Namespaces: map[string]NamespacePolicy{
+			"bob": {DefaultRuleMode: RuleModeOff, RuleModes: map[string]RuleMode{ruleDatabaseBindings: RuleModeWarn}},
+		},
+	}
+	effective = policy.ForNamespace("bob")
+	assert.Equal(t, RuleModeOff, effective.DefaultRuleMode)
+	assert.Equal(t, map[string]RuleMode{ruleDatabaseBindings: RuleModeWarn, ruleBindingReplicas: RuleModeEnforce}, effective.RuleModes)
+	assert.Equal(t, RuleModeAudit, policy.RuleModes[ruleDatabaseBindings])
+	effective = policy.ForNamespace("default")
+	assert.Equal(t, RuleModeWarn, effective.DefaultRuleMode)
+	assert.Equal(t, policy.RuleModes, effective.RuleModes)
+
+	var nilPolicy *Policy
+	assert.Equal(t, *DefaultPolicy(), nilPolicy.ForNamespace("default"))
+}
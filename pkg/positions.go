@@ -0,0 +1,78 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"fmt"
+	s "strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Position is a line and column in a manifest file, both starting at 1
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// FieldPositions maps the field paths of a resource, like spec.helidonApplications[1].name, to their positions
+type FieldPositions map[string]Position
+
+// Get the positions of the fields of a YAML node, with paths starting with the given prefix.  The position of
+// a field is the position of its key, the position of a list item is the position of the item.
+func getFieldPositions(node *yamlv3.Node, prefix string, positions FieldPositions) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, content := range node.Content {
+			getFieldPositions(content, prefix, positions)
+		}
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := key.Value
+			if prefix != "" {
+				path = prefix + "." + key.Value
+			}
+			positions[path] = Position{Line: key.Line, Column: key.Column}
+			getFieldPositions(value, path, positions)
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			path := fmt.Sprintf("%s[%d]", prefix, i)
+			positions[path] = Position{Line: item.Line, Column: item.Column}
+			getFieldPositions(item, path, positions)
+		}
+	case yamlv3.AliasNode:
+		if node.Alias != nil {
+			getFieldPositions(node.Alias, prefix, positions)
+		}
+	}
+}
+
+// Get the positions of the fields under a path, with the path removed from the field paths
+func (p FieldPositions) under(path string) FieldPositions {
+	positions := make(FieldPositions)
+	for field, position := range p {
+		if s.HasPrefix(field, path+".") {
+			positions[s.TrimPrefix(field, path+".")] = position
+		}
+	}
+	return positions
+}
+
+// Find the position of a field.  When the field isn't in the file, for example because it has a default value
+// or the path doesn't match the file exactly, the position of its closest parent is returned.
+func (p FieldPositions) find(field string) (Position, bool) {
+	for field != "" {
+		if position, ok := p[field]; ok {
+			return position, true
+		}
+		i := s.LastIndexAny(field, ".[")
+		if i < 0 {
+			break
+		}
+		field = field[:i]
+	}
+	return Position{}, false
+}
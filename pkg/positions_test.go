@@ -0,0 +1,40 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFieldPositions tests the positions of the fields of resources read from manifest files
+// GIVEN manifest files with a model and a list
+//  WHEN ReadManifests is called
+//  THEN the positions of the fields should be found, using the closest parent for fields not in the file
+func TestFieldPositions(t *testing.T) {
+	manifests, err := ReadManifests("../test/integ/testdata/invalid-helidon-names-model.yaml")
+	assert.Nil(t, err)
+	positions := manifests[0].Positions
+	assert.Equal(t, Position{Line: 6, Column: 3}, positions["metadata.name"])
+	assert.Equal(t, Position{Line: 11, Column: 7}, positions["spec.helidonApplications[0]"])
+	assert.Equal(t, Position{Line: 11, Column: 7}, positions["spec.helidonApplications[0].name"])
+
+	position, ok := positions.find("spec.helidonApplications[0].imagePullSecrets[1].name")
+	assert.True(t, ok)
+	assert.Equal(t, Position{Line: 15, Column: 11}, position)
+	position, ok = positions.find("spec.helidonApplications[0].port")
+	assert.True(t, ok)
+	assert.Equal(t, Position{Line: 11, Column: 7}, position)
+	_, ok = positions.find("status.state")
+	assert.False(t, ok)
+
+	dir := writeLookupManifests(t)
+	defer os.RemoveAll(dir)
+	manifests, err = ReadManifests(dir + "/list.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, Position{Line: 15, Column: 3}, manifests[1].Positions["metadata"])
+	assert.Equal(t, Position{Line: 16, Column: 5}, manifests[1].Positions["metadata.name"])
+}
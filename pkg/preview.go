@@ -0,0 +1,164 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	s "strings"
+
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	"go.uber.org/zap"
+)
+
+// PreviewRequest is the body of a preview request.  The model must be given, it is not read from the cluster so
+// that callers can't see the models of namespaces they can't access.
+type PreviewRequest struct {
+	Model   *v1beta1v8o.VerrazzanoModel   `json:"model"`
+	Binding *v1beta1v8o.VerrazzanoBinding `json:"binding"`
+}
+
+// Name of the WebLogic admin server assumed by the preview, the name is set in the domain and not in the model
+const previewWebLogicAdminServerName = "AdminServer"
+
+// Note on the service names of a preview, which are derived from the naming of the operators
+const previewServicesNote = "service names are approximate, they follow the naming of the operators and assume the WebLogic admin server is named " +
+	previewWebLogicAdminServerName
+
+// Preview contains the names of the resources the Verrazzano operator creates for a binding
+type Preview struct {
+	Binding    string             `json:"binding"`
+	Model      string             `json:"model"`
+	Hostnames  []PreviewHostname  `json:"hostnames"`
+	Namespaces []PreviewNamespace `json:"namespaces"`
+	// Tells that the service names are approximate
+	ServicesNote string `json:"servicesNote"`
+	// Validation messages for the generated names
+	Problems []string `json:"problems,omitempty"`
+}
+
+// PreviewHostname is a hostname created for a binding
+type PreviewHostname struct {
+	Hostname string `json:"hostname"`
+	Usage    string `json:"usage"`
+	Length   int    `json:"length"`
+}
+
+// PreviewNamespace contains the names of the resources created in a placement namespace
+type PreviewNamespace struct {
+	Cluster            string   `json:"cluster"`
+	Name               string   `json:"name"`
+	Services           []string `json:"services,omitempty"`
+	WebLogicDomainUIDs []string `json:"weblogicDomainUIDs,omitempty"`
+}
+
+// PreviewBinding returns the names of the resources the Verrazzano operator creates for a binding and its model
+func PreviewBinding(model v1beta1v8o.VerrazzanoModel, binding v1beta1v8o.VerrazzanoBinding, verrazzanoURI string) Preview {
+	preview := Preview{Binding: binding.Name, Model: model.Name, ServicesNote: previewServicesNote}
+	for _, hostname := range getGeneratedHostnames(binding, verrazzanoURI) {
+		preview.Hostnames = append(preview.Hostnames, PreviewHostname{Hostname: hostname.host, Usage: hostname.usage, Length: len(hostname.host)})
+	}
+
+	for _, placement := range binding.Spec.Placement {
+		for _, namespace := range placement.Namespaces {
+			previewNamespace := PreviewNamespace{Cluster: placement.Name, Name: namespace.Name}
+			for _, component := range namespace.Components {
+				services, domainUID := getComponentResourceNames(model, component.Name)
+				previewNamespace.Services = append(previewNamespace.Services, services...)
+				if domainUID != "" {
+					previewNamespace.WebLogicDomainUIDs = append(previewNamespace.WebLogicDomainUIDs, domainUID)
+				}
+			}
+			preview.Namespaces = append(preview.Namespaces, previewNamespace)
+		}
+	}
+
+	preview.Problems = problemMessages(validateGeneratedHostnames(binding, verrazzanoURI))
+	return preview
+}
+
+// Get the names of the services and the WebLogic domain UID created for a component of a model.  The WebLogic
+// operator creates a service for the admin server and for each cluster of a domain.
+func getComponentResourceNames(model v1beta1v8o.VerrazzanoModel, componentName string) ([]string, string) {
+	for _, helidon := range model.Spec.HelidonApplications {
+		if helidon.Name == componentName {
+			return []string{helidon.Name}, ""
+		}
+	}
+	for _, coherence := range model.Spec.CoherenceClusters {
+		if coherence.Name == componentName {
+			return []string{coherence.Name + "-wka"}, ""
+		}
+	}
+	for _, wd := range model.Spec.WeblogicDomains {
+		if wd.Name == componentName {
+			domainUID := webLogicDomainUID(wd)
+			services := []string{webLogicServiceName(domainUID, previewWebLogicAdminServerName)}
+			for _, cluster := range wd.DomainCRValues.Clusters {
+				services = append(services, webLogicServiceName(domainUID, "cluster-"+cluster.ClusterName))
+			}
+			return services, domainUID
+		}
+	}
+	for _, generic := range model.Spec.GenericComponents {
+		if generic.Name == componentName {
+			return []string{generic.Name}, ""
+		}
+	}
+	return nil, ""
+}
+
+// Get the name of a service created by the WebLogic operator, which lower cases the name and replaces underscores
+func webLogicServiceName(domainUID string, name string) string {
+	return s.ToLower(s.ReplaceAll(domainUID+"-"+name, "_", "-"))
+}
+
+// ServePreview receives preview requests containing a binding and its model and responds with the names of the
+// resources created for the binding
+func (sh *ServerHandler) ServePreview(w http.ResponseWriter, r *http.Request) {
+	zap.S().Infow("Received preview request")
+
+	var body []byte
+	if r.Body != nil {
+		if data, err := ioutil.ReadAll(r.Body); err == nil {
+			body = data
+		}
+	}
+	if len(body) == 0 {
+		zap.S().Errorw("empty request body received")
+		http.Error(w, "empty request body received", http.StatusBadRequest)
+		return
+	}
+
+	request := PreviewRequest{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		zap.S().Errorf("error with unmarshal of request body: %v", err)
+		http.Error(w, fmt.Sprintf("error with unmarshal of request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if request.Binding == nil {
+		zap.S().Errorw("preview request has no binding")
+		http.Error(w, "preview request has no binding", http.StatusBadRequest)
+		return
+	}
+
+	if request.Model == nil {
+		zap.S().Errorw("preview request has no model")
+		http.Error(w, "preview request has no model", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := json.Marshal(PreviewBinding(*request.Model, *request.Binding, sh.VerrazzanoConfig.URI()))
+	if err != nil {
+		zap.S().Errorf("error with marshal of response: %v", err)
+		http.Error(w, fmt.Sprintf("error with marshal of response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		zap.S().Errorf("error with write of response: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPreviewBinding tests the preview of the names of the resources created for a binding
+// GIVEN a VerrazzanoBinding, its VerrazzanoModel and a Verrazzano URI
+//  WHEN PreviewBinding is called
+//  THEN the hostnames, namespaces, services and WebLogic domain UIDs created for the binding should be returned
+func TestPreviewBinding(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+
+	preview := PreviewBinding(*model, *binding, "v8o.example.com")
+	assert.Equal(t, "bobs-books-binding", preview.Binding)
+	assert.Equal(t, "bobs-books-model", preview.Model)
+	assert.Equal(t, PreviewHostname{Hostname: "*.vmi.bobs-books-binding.v8o.example.com", Usage: "VMI domain name", Length: 40}, preview.Hostnames[0])
+	assert.Len(t, preview.Namespaces, 3)
+	assert.Equal(t, PreviewNamespace{Cluster: "local", Name: "robert", Services: []string{"roberts-helidon-stock-application", "roberts-coherence-wka"}}, preview.Namespaces[1])
+	assert.Equal(t, []string{"bobs-bookstore"}, preview.Namespaces[2].WebLogicDomainUIDs)
+	assert.Equal(t, []string{"bobs-bookstore-adminserver", "bobs-bookstore-cluster-cluster-1"}, preview.Namespaces[2].Services)
+	assert.Contains(t, preview.ServicesNote, "approximate")
+	assert.Empty(t, preview.Problems)
+
+	preview = PreviewBinding(*model, *binding, "")
+	assert.Equal(t, []string{"the Verrazzano URI is not known, the hostnames of binding bobs-books-binding don't include it and can't be validated"}, preview.Problems)
+}
+
+// TestServePreview tests the preview endpoint
+// GIVEN a preview request
+//  WHEN ServePreview is called with the request
+//  THEN the response should contain the preview or an error if the request has no binding or no model
+func TestServePreview(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	sh := &ServerHandler{VerrazzanoConfig: NewVerrazzanoConfig("v8o.example.com")}
+
+	body, err := json.Marshal(PreviewRequest{Model: model, Binding: binding})
+	assert.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	sh.ServePreview(recorder, httptest.NewRequest(http.MethodPost, "/preview", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	preview := Preview{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &preview))
+	assert.Equal(t, "grafana.vmi.bobs-books-binding.v8o.example.com", preview.Hostnames[1].Hostname)
+
+	recorder = httptest.NewRecorder()
+	sh.ServePreview(recorder, httptest.NewRequest(http.MethodPost, "/preview", bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "preview request has no binding")
+
+	body, err = json.Marshal(PreviewRequest{Binding: binding})
+	assert.Nil(t, err)
+	recorder = httptest.NewRecorder()
+	sh.ServePreview(recorder, httptest.NewRequest(http.MethodPost, "/preview", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "preview request has no model")
+
+	recorder = httptest.NewRecorder()
+	sh.ServePreview(recorder, httptest.NewRequest(http.MethodPost, "/preview", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,303 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	s "strings"
+)
+
+// Report formats supported by WriteReport
+const (
+	ReportFormatText  = "text"
+	ReportFormatJSON  = "json"
+	ReportFormatJUnit = "junit"
+	ReportFormatSARIF = "sarif"
+)
+
+// Problem is a single problem found by a validation
+type Problem struct {
+	// Path of the field with the problem, like spec.helidonApplications[1].name, empty if not known
+	Field string `json:"field,omitempty"`
+	// Type of the problem, like "Invalid value", empty if not known
+	Type string `json:"type,omitempty"`
+	// ID of the rule of the check that found the problem, like binding-replicas, empty if not known
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+	// Position of the field in the file, zero if not known
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// Types of the problems of fields, the types of the field errors of the API server
+const (
+	problemTypeInvalid      = "Invalid value"
+	problemTypeDuplicate    = "Duplicate value"
+	problemTypeRequired     = "Required value"
+	problemTypeNotSupported = "Unsupported value"
+	problemTypeForbidden    = "Forbidden"
+	problemTypeNotFound     = "Not found"
+)
+
+// Create a problem of a field.  The message is prefixed by the path of the field and the type of the problem, like
+// "spec.x[0].y: Invalid value: detail", or only by the path of the field when the type is empty.
+func fieldProblem(field string, problemType string, detail string) Problem {
+	message := field + ": " + detail
+	if problemType != "" {
+		message = field + ": " + problemType + ": " + detail
+	}
+	return Problem{Field: field, Type: problemType, Message: message}
+}
+
+// Create a problem of a field with a message that doesn't start with the path of the field, like the problems of
+// references to other resources
+func newFieldProblem(field string, problemType string, format string, args ...interface{}) Problem {
+	return Problem{Field: field, Type: problemType, Message: fmt.Sprintf(format, args...)}
+}
+
+// Create a problem that is not reported for a field
+func newProblem(format string, args ...interface{}) Problem {
+	return Problem{Message: fmt.Sprintf(format, args...)}
+}
+
+// Get the messages of a list of problems
+func problemMessages(problems []Problem) []string {
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Message)
+	}
+	return messages
+}
+
+// Get the message of a list of problems, their messages separated by "; "
+func problemsMessage(problems []Problem) string {
+	return s.Join(problemMessages(problems), "; ")
+}
+
+// Problems returns the problems of the validation, problems of fields get the positions of the fields in the file
+// when the result has them.  A result that only has a message has a single problem with the message.
+func (r ValidationResult) Problems() []Problem {
+	if len(r.problems) == 0 && r.Message != "" {
+		return []Problem{{Message: r.Message}}
+	}
+	var problems []Problem
+	for _, problem := range r.problems {
+		if position, ok := r.Positions.find(problem.Field); ok {
+			problem.Line, problem.Column = position.Line, position.Column
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// WriteReport writes the validation results in one of the report formats
+func WriteReport(w io.Writer, format string, results []ValidationResult) error {
+	switch format {
+	case ReportFormatText:
+		return writeTextReport(w, results)
+	case ReportFormatJSON:
+		return writeJSONReport(w, results)
+	case ReportFormatJUnit:
+		return writeJUnitReport(w, results)
+	case ReportFormatSARIF:
+		return writeSARIFReport(w, results)
+	}
+	return fmt.Errorf("unsupported report format %s, supported formats are %s, %s, %s and %s", format, ReportFormatText, ReportFormatJSON, ReportFormatJUnit, ReportFormatSARIF)
+}
+
+// Write one line for each valid resource and each problem
+func writeTextReport(w io.Writer, results []ValidationResult) error {
+	for _, result := range results {
+		problems := result.Problems()
+		if len(problems) == 0 {
+			if _, err := fmt.Fprintf(w, "%s: %s %s/%s is valid\n", result.Path, result.Kind, result.Namespace, result.Name); err != nil {
+				return err
+			}
+		}
+		for _, problem := range problems {
+			if _, err := fmt.Fprintf(w, "%s: %s %s/%s: %s\n", problem.location(result.Path), result.Kind, result.Namespace, result.Name, problem.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Location of a problem in the form file:line:column, or file if the position of the problem is not known
+func (p Problem) location(path string) string {
+	if p.Line == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s:%d:%d", path, p.Line, p.Column)
+}
+
+// jsonResult is a validation result in a JSON report
+type jsonResult struct {
+	Path      string    `json:"path"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Valid     bool      `json:"valid"`
+	Problems  []Problem `json:"problems,omitempty"`
+}
+
+func writeJSONReport(w io.Writer, results []ValidationResult) error {
+	report := struct {
+		Results []jsonResult `json:"results"`
+	}{Results: []jsonResult{}}
+	for _, result := range results {
+		report.Results = append(report.Results, jsonResult{Path: result.Path, Kind: result.Kind, Namespace: result.Namespace,
+			Name: result.Name, Valid: result.Message == "", Problems: result.Problems()})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// JUnit XML report with a test case for each validated resource
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnitReport(w io.Writer, results []ValidationResult) error {
+	suite := junitTestSuite{Name: "verrazzano-validate", Tests: len(results)}
+	for _, result := range results {
+		testCase := junitTestCase{Name: fmt.Sprintf("%s %s/%s", result.Kind, result.Namespace, result.Name), ClassName: result.Path}
+		if problems := result.Problems(); len(problems) > 0 {
+			var lines []string
+			for _, problem := range problems {
+				lines = append(lines, problem.location(result.Path)+": "+problem.Message)
+			}
+			testCase.Failure = &junitFailure{Message: fmt.Sprintf("%d validation problem(s)", len(problems)), Type: "ValidationFailed", Text: s.Join(lines, "\n")}
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// SARIF 2.1.0 report with a result for each problem
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+func writeSARIFReport(w io.Writer, results []ValidationResult) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "verrazzano-validate", InformationURI: "https://github.com/verrazzano/verrazzano-admission-controllers"}},
+		Results: []sarifResult{},
+	}
+	for _, result := range results {
+		for _, problem := range result.Problems() {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepathToURI(result.Path)}}}
+			if problem.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: problem.Line, StartColumn: problem.Column}
+			}
+			name := fmt.Sprintf("%s/%s/%s", result.Kind, result.Namespace, result.Name)
+			if problem.Field != "" {
+				name += "/" + problem.Field
+			}
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: name}}
+			run.Results = append(run.Results, sarifResult{RuleID: sarifRuleID(result.Kind, problem), Level: "error",
+				Message: sarifMessage{Text: problem.Message}, Locations: []sarifLocation{location}})
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Version: "2.1.0", Schema: "https://json.schemastore.org/sarif-2.1.0.json", Runs: []sarifRun{run}})
+}
+
+// Rule ID of a problem, the ID of its rule or else made of the kind of the resource and the type of the problem
+func sarifRuleID(kind string, problem Problem) string {
+	if problem.Rule != "" {
+		return problem.Rule
+	}
+	problemType := "ValidationFailed"
+	if problem.Type != "" {
+		problemType = s.ReplaceAll(s.Title(problem.Type), " ", "")
+	}
+	return kind + "/" + problemType
+}
+
+// Convert a file path to a relative URI reference
+func filepathToURI(path string) string {
+	return s.ReplaceAll(s.TrimPrefix(path, "./"), "\\", "/")
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var reportResults = []ValidationResult{
+	newValidationResult(Manifest{Path: "model.yaml"}, "VerrazzanoModel", "default", "model", nil),
+	newValidationResult(Manifest{Path: "./binding.yaml", Positions: FieldPositions{"spec.databaseBindings[0]": {Line: 12, Column: 7}}},
+		"VerrazzanoBinding", "default", "binding", []Problem{
+			fieldProblem("spec.placement[0].namespaces[1].name", problemTypeInvalid, "\"bad_name\": not valid"),
+			fieldProblem("spec.databaseBindings[0].url", problemTypeRequired, "database binding mysql must have a URL"),
+			withRule(ruleClusterReferences, []Problem{newProblem("binding references cluster(s) \"local\" that do not exist in namespace default")})[0],
+		}),
+}
+
+// TestValidationResultProblems tests the problems of validation results
+// GIVEN validation results with and without problems
+//  WHEN Problems is called
+//  THEN the problems should be returned with the positions of their fields
+func TestValidationResultProblems(t *testing.T) {
+	assert.Empty(t, reportResults[0].Problems())
+	assert.Equal(t, []Problem{
+		{Field: "spec.placement[0].namespaces[1].name", Type: "Invalid value", Message: "spec.placement[0].namespaces[1].name: Invalid value: \"bad_name\": not valid"},
+		{Field: "spec.databaseBindings[0].url", Type: "Required value", Message: "spec.databaseBindings[0].url: Required value: database binding mysql must have a URL",
+			Line: 12, Column: 7},
+		{Rule: ruleClusterReferences, Message: "binding references cluster(s) \"local\" that do not exist in namespace default"},
+	}, reportResults[1].Problems())
+
+	result := ValidationResult{Kind: "VerrazzanoModel", Message: "model is not valid"}
+	assert.Equal(t, []Problem{{Message: "model is not valid"}}, result.Problems())
+}
+
+// TestWriteReport tests writing of validation reports
+// GIVEN validation results
+//  WHEN WriteReport is called with each report format
+//  THEN the report should contain the results in the format
+func TestWriteReport(t *testing.T) {
+	var out bytes.Buffer
+	assert.Nil(t, WriteReport(&out, ReportFormatText, reportResults))
+	assert.Equal(t, "model.yaml: VerrazzanoModel default/model is valid\n", out.String()[:len("model.yaml: VerrazzanoModel default/model is valid\n")])
+	assert.Contains(t, out.String(), "./binding.yaml:12:7: VerrazzanoBinding default/binding: spec.databaseBindings[0].url: Required value")
+	assert.Contains(t, out.String(), "./binding.yaml: VerrazzanoBinding default/binding: binding references cluster(s)")
+
+	out.Reset()
+	assert.Nil(t, WriteReport(&out, ReportFormatJSON, reportResults))
+	report := struct {
+		Results []jsonResult `json:"results"`
+	}{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &report))
+	assert.True(t, report.Results[0].Valid)
+	assert.False(t, report.Results[1].Valid)
+	assert.Len(t, report.Results[1].Problems, 3)
+
+	out.Reset()
+	assert.Nil(t, WriteReport(&out, ReportFormatJUnit, reportResults))
+	suite := junitTestSuite{}
+	assert.Nil(t, xml.Unmarshal(out.Bytes(), &suite))
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Nil(t, suite.TestCases[0].Failure)
+	assert.Equal(t, "VerrazzanoBinding default/binding", suite.TestCases[1].Name)
+	assert.Equal(t, "3 validation problem(s)", suite.TestCases[1].Failure.Message)
+	assert.Contains(t, suite.TestCases[1].Failure.Text, "./binding.yaml:12:7: spec.databaseBindings[0].url")
+
+	out.Reset()
+	assert.Nil(t, WriteReport(&out, ReportFormatSARIF, reportResults))
+	sarif := sarifLog{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &sarif))
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Len(t, sarif.Runs[0].Results, 3)
+	assert.Equal(t, "VerrazzanoBinding/RequiredValue", sarif.Runs[0].Results[1].RuleID)
+	assert.Equal(t, "cluster-references", sarif.Runs[0].Results[2].RuleID)
+	assert.Equal(t, "VerrazzanoBinding/ValidationFailed", sarifRuleID("VerrazzanoBinding", newProblem("binding is not valid")))
+	assert.Equal(t, "binding.yaml", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, &sarifRegion{StartLine: 12, StartColumn: 7}, sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "VerrazzanoBinding/default/binding/spec.placement[0].namespaces[1].name", sarif.Runs[0].Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	assert.NotNil(t, WriteReport(&out, "html", reportResults))
+}
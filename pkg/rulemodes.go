@@ -0,0 +1,290 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	s "strings"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/api/admission/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuleMode is how the problems of a rule are handled
+type RuleMode string
+
+// Modes of the rules
+const (
+	// The request is denied
+	RuleModeEnforce RuleMode = "enforce"
+	// The request is allowed with a warning returned to the client
+	RuleModeWarn RuleMode = "warn"
+	// The request is allowed, the problem is logged and added to the audit annotations of the request
+	RuleModeAudit RuleMode = "audit"
+	// The problem is ignored
+	RuleModeOff RuleMode = "off"
+)
+
+// IDs of the rules of the checks of the model, binding and secret validators
+const (
+	// The object of the request can't be read
+	ruleInvalidObject = "invalid-object"
+	// Names used as Kubernetes resource names must be valid
+	ruleResourceNames = "resource-names"
+	// The validation must complete before the deadline
+	ruleValidationTimeout = "validation-timeout"
+
+	ruleWebLogicClusters      = "weblogic-clusters"
+	ruleSecretReferences      = "secret-references"
+	ruleConfigMapReferences   = "config-map-references"
+	ruleWebLogicDomains       = "weblogic-domains"
+	ruleWebLogicDomainValues  = "weblogic-domain-values"
+	ruleCoherenceClusters     = "coherence-clusters"
+	ruleHelidonApplications   = "helidon-applications"
+	ruleHelidonPlacementPorts = "helidon-placement-ports"
+	ruleGenericComponents     = "generic-components"
+	// A model can't be deleted while bindings use it
+	ruleModelInUse = "model-in-use"
+
+	// A binding must reference an existing model
+	ruleBindingModel = "binding-model"
+	// The model referenced by a binding must be readable
+	ruleModelLookup = "model-lookup"
+	// The components of a binding must exist in its model and be bound once
+	ruleBindingComponents      = "binding-components"
+	ruleGeneratedHostnames     = "generated-hostnames"
+	ruleClusterReferences      = "cluster-references"
+	rulePlacementNamespaces    = "placement-namespaces"
+	ruleIngressBindings        = "ingress-bindings"
+	ruleBindingReplicas        = "binding-replicas"
+	ruleManagedClusterReplicas = "managed-cluster-replicas"
+	ruleDatabaseBindings       = "database-bindings"
+
+	// A secret can't be deleted while models reference it
+	ruleSecretInUse = "secret-in-use"
+
+	// Label of the problems of validators that don't set a rule
+	ruleUnclassified = "unclassified"
+)
+
+// Rules that are always enforced because the object is structurally invalid or the request would delete a resource
+// that is in use.  Problems without a rule are always enforced too.
+var protectedRules = map[string]bool{
+	ruleInvalidObject:     true,
+	ruleResourceNames:     true,
+	ruleValidationTimeout: true,
+	ruleModelInUse:        true,
+	ruleBindingModel:      true,
+	ruleBindingComponents: true,
+}
+
+// Check whether the problems of a rule are always enforced
+func isProtectedRule(rule string) bool {
+	return rule == "" || protectedRules[rule]
+}
+
+// Set the rule of the problems found by a check
+func withRule(rule string, problems []Problem) []Problem {
+	for i := range problems {
+		problems[i].Rule = rule
+	}
+	return problems
+}
+
+// Label of a namespace setting the default mode of the rules for its resources
+const namespaceRuleModeLabel = "verrazzano.io/admission-mode"
+
+// Annotation of a namespace setting the modes of rules for its resources, like
+// "binding-replicas=warn,database-bindings=audit"
+const namespaceRuleModesAnnotation = "verrazzano.io/admission-rule-modes"
+
+// Key of the audit annotation containing the problems of the rules in audit mode
+const auditedProblemsAnnotation = "audited-problems"
+
+// Modes of the rules in the order they are listed in messages
+var ruleModes = []RuleMode{RuleModeEnforce, RuleModeWarn, RuleModeAudit, RuleModeOff}
+
+// Check whether a rule mode is supported
+func (m RuleMode) valid() bool {
+	for _, mode := range ruleModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the names of the rule modes, used in messages
+func ruleModeNames() string {
+	var names []string
+	for _, mode := range ruleModes {
+		names = append(names, string(mode))
+	}
+	return s.Join(names, ", ")
+}
+
+// Get the mode of a rule in a policy, protected rules are always enforced
+func (p Policy) ruleMode(rule string) RuleMode {
+	if isProtectedRule(rule) {
+		return RuleModeEnforce
+	}
+	if mode, ok := p.RuleModes[rule]; ok {
+		return mode
+	}
+	if p.DefaultRuleMode != "" {
+		return p.DefaultRuleMode
+	}
+	return RuleModeEnforce
+}
+
+// Get whether all the rules of a policy are enforced, in which case the problems don't have to be split
+func (p Policy) enforcesAllRules() bool {
+	if p.DefaultRuleMode != "" && p.DefaultRuleMode != RuleModeEnforce {
+		return false
+	}
+	for _, mode := range p.RuleModes {
+		if mode != RuleModeEnforce {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge the rule modes of a namespace into the global rule modes
+func mergeRuleModes(modes map[string]RuleMode, overrides map[string]RuleMode) map[string]RuleMode {
+	if len(overrides) == 0 {
+		return modes
+	}
+	merged := make(map[string]RuleMode)
+	for rule, mode := range modes {
+		merged[rule] = mode
+	}
+	for rule, mode := range overrides {
+		merged[rule] = mode
+	}
+	return merged
+}
+
+// Parse the rule modes of a namespace annotation, like "binding-replicas=warn,database-bindings=audit"
+func parseRuleModes(value string) (map[string]RuleMode, error) {
+	modes := make(map[string]RuleMode)
+	for _, entry := range s.Split(value, ",") {
+		entry = s.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := s.SplitN(entry, "=", 2)
+		if len(parts) != 2 || s.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid rule mode %s, must be rule=mode", entry)
+		}
+		modes[s.TrimSpace(parts[0])] = RuleMode(s.TrimSpace(parts[1]))
+	}
+	if err := checkRuleModes("", modes); err != nil {
+		return nil, err
+	}
+	return modes, nil
+}
+
+// Get the policy of the namespace of a request with the rule modes set by the label and annotation of the
+// namespace.  The modes set by the policy for a rule take precedence over the default mode of the label.  The
+// namespace is only fetched when a request has problems.
+func requestRulePolicy(ctx context.Context, request *ValidatorRequest) Policy {
+	policy := request.Policy.ForNamespace(request.Namespace)
+	if request.Namespace == "" || request.Clientsets == nil || request.Clientsets.K8sClient == nil {
+		return policy
+	}
+
+	start := time.Now()
+	namespace, err := request.Clientsets.K8sClient.CoreV1().Namespaces().Get(ctx, request.Namespace, metav1.GetOptions{})
+	observeLookup(lookupGetNamespace, start)
+	recordTimedOutLookup(ctx, err, "namespace "+request.Namespace)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			zap.S().Errorf("failed to get namespace %s, using the rule modes of the policy: %v", request.Namespace, err)
+		}
+		return policy
+	}
+
+	if value, ok := namespace.Labels[namespaceRuleModeLabel]; ok {
+		if mode := RuleMode(value); mode.valid() {
+			policy.DefaultRuleMode = mode
+		} else {
+			zap.S().Errorf("ignoring invalid label %s=%s of namespace %s, must be one of %s", namespaceRuleModeLabel, value,
+				request.Namespace, ruleModeNames())
+		}
+	}
+	if value, ok := namespace.Annotations[namespaceRuleModesAnnotation]; ok {
+		if modes, err := parseRuleModes(value); err == nil {
+			policy.RuleModes = mergeRuleModes(policy.RuleModes, modes)
+		} else {
+			zap.S().Errorf("ignoring invalid annotation %s of namespace %s: %v", namespaceRuleModesAnnotation, request.Namespace, err)
+		}
+	}
+	return policy
+}
+
+// Handle the problems of a validation according to the modes of their rules.  The request is denied with the
+// problems of the enforced rules, the problems of the other rules are returned as warnings or audited.
+func applyRuleModes(ctx context.Context, request *ValidatorRequest, problems []Problem) ValidatorResult {
+	policy := requestRulePolicy(ctx, request)
+	if policy.enforcesAllRules() {
+		return DenyProblems(problems...)
+	}
+
+	var denied []Problem
+	var warnings, audited []string
+	for _, problem := range problems {
+		rule := problemRule(problem)
+		mode := policy.ruleMode(problem.Rule)
+		if mode != RuleModeEnforce {
+			ruleViolations.WithLabelValues(request.Kind.Kind, rule, string(mode)).Inc()
+		}
+		switch mode {
+		case RuleModeWarn:
+			zap.S().Warnf("allowing %s %s:%s with a warning for rule %s: %s", request.Kind.Kind, request.Namespace, request.Name, rule, problem.Message)
+			warnings = append(warnings, problem.Message)
+		case RuleModeAudit:
+			zap.S().Infof("audit of %s %s:%s for rule %s: %s", request.Kind.Kind, request.Namespace, request.Name, rule, problem.Message)
+			audited = append(audited, problem.Message)
+		case RuleModeOff:
+			zap.S().Debugf("ignoring problem of %s %s:%s for rule %s: %s", request.Kind.Kind, request.Namespace, request.Name, rule, problem.Message)
+		default:
+			denied = append(denied, problem)
+		}
+	}
+
+	result := problemsResult(denied)
+	result.Warnings = warnings
+	result.AuditedProblems = audited
+	return result
+}
+
+// Admission response with the warnings returned to the client, the admission API of this version of Kubernetes
+// doesn't have them yet.  API servers before 1.19 ignore the warnings.
+type warningAdmissionResponse struct {
+	*v1beta1.AdmissionResponse
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Admission review with a response containing warnings
+type warningAdmissionReview struct {
+	metav1.TypeMeta
+	Response *warningAdmissionResponse `json:"response,omitempty"`
+}
+
+// Add the warnings and the audited problems of a validation result to an admission review
+func withRuleModeResults(arResponse v1beta1.AdmissionReview, result ValidatorResult) warningAdmissionReview {
+	if len(result.AuditedProblems) > 0 {
+		if arResponse.Response.AuditAnnotations == nil {
+			arResponse.Response.AuditAnnotations = make(map[string]string)
+		}
+		arResponse.Response.AuditAnnotations[auditedProblemsAnnotation] = s.Join(result.AuditedProblems, "; ")
+	}
+	return warningAdmissionReview{TypeMeta: arResponse.TypeMeta,
+		Response: &warningAdmissionResponse{AdmissionResponse: arResponse.Response, Warnings: result.Warnings}}
+}
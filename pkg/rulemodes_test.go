@@ -0,0 +1,321 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Messages of the problems found by the validator used by the rule mode tests, one for each of three rules
+const (
+	replicasProblem  = "spec.helidonBindings[0].replicas: Invalid value: 10: must be less than or equal to 5"
+	databaseProblem  = "spec.databaseBindings[0].url: Forbidden: database host db.example.com of database binding mysql is not allowed by policy"
+	componentProblem = "Component in bindings does not exist in model definition. Invalid Component: [hello]"
+)
+
+// Problems found by the validator used by the rule mode tests, the component problem has a protected rule
+var ruleModeProblems = []Problem{
+	{Field: "spec.helidonBindings[0].replicas", Type: problemTypeInvalid, Rule: ruleBindingReplicas, Message: replicasProblem},
+	{Field: "spec.databaseBindings[0].url", Type: problemTypeForbidden, Rule: ruleDatabaseBindings, Message: databaseProblem},
+	{Rule: ruleBindingComponents, Message: componentProblem},
+}
+
+// Validator denying every request with the same problems
+type testProblemsValidator struct {
+	problems []Problem
+}
+
+func (testProblemsValidator) Registration() ValidatorRegistration {
+	return ValidatorRegistration{Kind: "VerrazzanoBinding", Group: "verrazzano.io", Version: "v1beta1",
+		Resource: "verrazzanobindings", Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create}}
+}
+
+func (v testProblemsValidator) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	return DenyProblems(v.problems...)
+}
+
+// Create a namespace with labels and annotations
+func newRuleModeNamespace(name string, labels map[string]string, annotations map[string]string) *corev1.Namespace {
+	namespace := &corev1.Namespace{}
+	namespace.Name = name
+	namespace.Labels = labels
+	namespace.Annotations = annotations
+	return namespace
+}
+
+// TestParseRuleModes tests parsing of the rule modes of a namespace annotation
+// GIVEN annotation values with valid and invalid rule modes
+//  WHEN parseRuleModes is called
+//  THEN the modes should be returned by rule, or an error for invalid values and protected rules
+func TestParseRuleModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]RuleMode
+	}{
+		{name: "TestParseRuleModes", value: " binding-replicas = warn,database-bindings=audit,resource-names=enforce,",
+			expected: map[string]RuleMode{ruleBindingReplicas: RuleModeWarn, ruleDatabaseBindings: RuleModeAudit, ruleResourceNames: RuleModeEnforce}},
+		{name: "TestParseEmptyRuleModes", value: "", expected: map[string]RuleMode{}},
+		{name: "TestParseInvalidRuleMode", value: "binding-replicas=dryrun"},
+		{name: "TestParseRuleModeWithoutMode", value: "binding-replicas"},
+		{name: "TestParseRuleModeWithoutRule", value: "=warn"},
+		{name: "TestParseProtectedRuleMode", value: "model-in-use=off"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modes, err := parseRuleModes(test.value)
+			if test.expected == nil {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, modes)
+			}
+		})
+	}
+}
+
+// TestApplyRuleModes tests handling of the problems of a validation by the modes of their rules
+// GIVEN a validator finding problems for three rules and rule modes set by the policy and the namespace
+//  WHEN the registry validates a request
+//  THEN the problems of enforced rules should deny the request and the others be returned as warnings, audited or ignored
+func TestApplyRuleModes(t *testing.T) {
+	message := replicasProblem + "; " + databaseProblem + "; " + componentProblem
+	registry := &ValidatorRegistry{}
+	assert.Nil(t, registry.Register(testProblemsValidator{problems: ruleModeProblems}))
+
+	tests := []struct {
+		name      string
+		policy    *Policy
+		namespace *corev1.Namespace
+		denied    string
+		warnings  []string
+		audited   []string
+	}{
+		{
+			name:   "TestApplyDefaultRuleModes",
+			policy: DefaultPolicy(),
+			denied: message,
+		}, {
+			name:     "TestApplyPolicyRuleModes",
+			policy:   &Policy{RuleModes: map[string]RuleMode{ruleBindingReplicas: RuleModeWarn, ruleDatabaseBindings: RuleModeAudit}},
+			denied:   componentProblem,
+			warnings: []string{replicasProblem},
+			audited:  []string{databaseProblem},
+		}, {
+			name: "TestApplyPolicyNamespaceRuleModes",
+			policy: &Policy{DefaultRuleMode: RuleModeOff, Namespaces: map[string]NamespacePolicy{
+				"bob": {RuleModes: map[string]RuleMode{ruleDatabaseBindings: RuleModeWarn}}}},
+			denied:   componentProblem,
+			warnings: []string{databaseProblem},
+		}, {
+			name:      "TestApplyNamespaceLabelRuleMode",
+			policy:    &Policy{RuleModes: map[string]RuleMode{ruleDatabaseBindings: RuleModeEnforce}},
+			namespace: newRuleModeNamespace("bob", map[string]string{namespaceRuleModeLabel: "audit"}, nil),
+			denied:    databaseProblem + "; " + componentProblem,
+			audited:   []string{replicasProblem},
+		}, {
+			name:   "TestApplyNamespaceAnnotationRuleModes",
+			policy: &Policy{RuleModes: map[string]RuleMode{ruleDatabaseBindings: RuleModeWarn}},
+			namespace: newRuleModeNamespace("bob", nil,
+				map[string]string{namespaceRuleModesAnnotation: "database-bindings=enforce,binding-replicas=off"}),
+			denied: databaseProblem + "; " + componentProblem,
+		}, {
+			name:   "TestApplyInvalidNamespaceRuleModes",
+			policy: DefaultPolicy(),
+			namespace: newRuleModeNamespace("bob", map[string]string{namespaceRuleModeLabel: "dryrun"},
+				map[string]string{namespaceRuleModesAnnotation: "binding-replicas"}),
+			denied: message,
+		}, {
+			name:      "TestApplyOtherNamespaceRuleModes",
+			policy:    DefaultPolicy(),
+			namespace: newRuleModeNamespace("alice", map[string]string{namespaceRuleModeLabel: "off"}, nil),
+			denied:    message,
+		}, {
+			name:   "TestApplyProtectedRuleModes",
+			policy: &Policy{DefaultRuleMode: RuleModeOff, RuleModes: map[string]RuleMode{ruleBindingComponents: RuleModeOff}},
+			denied: componentProblem,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset()
+			if test.namespace != nil {
+				k8sClient = k8sfake.NewSimpleClientset(test.namespace)
+			}
+			result := registry.Validate(context.TODO(), &ValidatorRequest{
+				AdmissionRequest: &v1beta1.AdmissionRequest{
+					Kind:      metav1.GroupVersionKind{Kind: "VerrazzanoBinding"},
+					Namespace: "bob",
+					Name:      "bobs-books-binding",
+					Operation: v1beta1.Create,
+				},
+				Clientsets: &Clientsets{K8sClient: k8sClient},
+				Policy:     test.policy,
+			})
+			assert.Equal(t, test.denied == "", result.Allowed)
+			assert.Equal(t, test.denied, result.Message)
+			assert.Equal(t, test.warnings, result.Warnings)
+			assert.Equal(t, test.audited, result.AuditedProblems)
+		})
+	}
+}
+
+// TestApplyRuleModesMetrics tests counting of the problems of rules that are not enforced
+// GIVEN a policy warning about a rule
+//  WHEN a request with a problem for the rule is validated
+//  THEN the problem should be counted by kind, rule and mode
+func TestApplyRuleModesMetrics(t *testing.T) {
+	violations := testutil.ToFloat64(ruleViolations.WithLabelValues("VerrazzanoBinding", ruleBindingReplicas, "warn"))
+	result := applyRuleModes(context.TODO(), &ValidatorRequest{
+		AdmissionRequest: &v1beta1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: "VerrazzanoBinding"}, Namespace: "bob"},
+		Policy:           &Policy{DefaultRuleMode: RuleModeWarn},
+	}, ruleModeProblems[:1])
+	assert.True(t, result.Allowed)
+	assert.Equal(t, violations+1, testutil.ToFloat64(ruleViolations.WithLabelValues("VerrazzanoBinding", ruleBindingReplicas, "warn")))
+}
+
+// TestWithRuleModeResults tests the admission response of a request with warnings and audited problems
+// GIVEN an allowed admission review and a validation result with warnings and audited problems
+//  WHEN withRuleModeResults is called and the review is marshaled
+//  THEN the response should contain the warnings and an audit annotation with the audited problems
+func TestWithRuleModeResults(t *testing.T) {
+	arResponse := v1beta1.AdmissionReview{Response: &v1beta1.AdmissionResponse{UID: "1234", Allowed: true}}
+	result := Allow()
+	result.Warnings = []string{replicasProblem}
+	result.AuditedProblems = []string{databaseProblem, componentProblem}
+	data, err := json.Marshal(withRuleModeResults(arResponse, result))
+	assert.Nil(t, err)
+
+	var response struct {
+		Response struct {
+			UID              string            `json:"uid"`
+			Allowed          bool              `json:"allowed"`
+			Warnings         []string          `json:"warnings"`
+			AuditAnnotations map[string]string `json:"auditAnnotations"`
+		} `json:"response"`
+	}
+	assert.Nil(t, json.Unmarshal(data, &response))
+	assert.Equal(t, "1234", response.Response.UID)
+	assert.True(t, response.Response.Allowed)
+	assert.Equal(t, []string{replicasProblem}, response.Response.Warnings)
+	assert.Equal(t, map[string]string{auditedProblemsAnnotation: databaseProblem + "; " + componentProblem}, response.Response.AuditAnnotations)
+
+	// The API server reads the response like any admission review
+	review := v1beta1.AdmissionReview{}
+	assert.Nil(t, json.Unmarshal(data, &review))
+	assert.Equal(t, arResponse.Response, review.Response)
+
+	data, err = json.Marshal(withRuleModeResults(arResponse, Allow()))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "warnings")
+}
+
+// TestRuleModesRunEveryCheck tests that the checks of a binding run when the problems of a rule are not enforced
+// GIVEN a binding with too many replicas and a placement in the default namespace, and policies not enforcing the rules
+//  WHEN the binding validator validates the creation of the binding
+//  THEN the problems of the enforced rule should deny the request and the others be returned as warnings
+func TestRuleModesRunEveryCheck(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	binding.Spec.Placement[0].Namespaces[0].Name = "default"
+	cluster := &v1beta1v8o.VerrazzanoManagedCluster{}
+	cluster.Namespace = "default"
+	cluster.Name = "local"
+	raw, err := json.Marshal(binding)
+	assert.Nil(t, err)
+	registry := &ValidatorRegistry{validators: []Validator{bindingValidator{}}}
+
+	tests := []struct {
+		name     string
+		modes    map[string]RuleMode
+		rules    []string
+		warnings int
+	}{
+		{name: "TestEnforceAllRules", rules: []string{rulePlacementNamespaces, ruleBindingReplicas, ruleBindingReplicas}},
+		{name: "TestWarnReplicas", modes: map[string]RuleMode{ruleBindingReplicas: RuleModeWarn}, rules: []string{rulePlacementNamespaces}, warnings: 2},
+		{name: "TestWarnPlacementNamespaces", modes: map[string]RuleMode{rulePlacementNamespaces: RuleModeWarn},
+			rules: []string{ruleBindingReplicas, ruleBindingReplicas}, warnings: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := registry.Validate(context.TODO(), &ValidatorRequest{
+				AdmissionRequest: &v1beta1.AdmissionRequest{
+					Kind:      metav1.GroupVersionKind{Kind: "VerrazzanoBinding"},
+					Namespace: "default",
+					Name:      binding.Name,
+					Operation: v1beta1.Create,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+				Clientsets: &Clientsets{V8oClient: NewFakeVzClient(model, cluster),
+					K8sClient: k8sfake.NewSimpleClientset(newSecret("default", "mysql-credentials", "hello"))},
+				VerrazzanoURI: "v8o.example.com",
+				Policy:        &Policy{MaxBindingReplicas: 2, RuleModes: test.modes},
+			})
+			var rules []string
+			for _, problem := range result.Problems {
+				rules = append(rules, problem.Rule)
+			}
+			assert.False(t, result.Allowed)
+			assert.Equal(t, test.rules, rules)
+			assert.Len(t, result.Warnings, test.warnings)
+		})
+	}
+}
+
+// TestRuleModesProtectModelInUse tests that the deletion of a model used by a binding is denied whatever the modes
+// GIVEN a model used by a binding and a policy turning every rule off
+//  WHEN the model validator validates the deletion of the model
+//  THEN the request should be denied
+func TestRuleModesProtectModelInUse(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	binding := ReadBinding("testdata/bobs-books-v2-binding.yaml")
+	registry := &ValidatorRegistry{validators: []Validator{modelValidator{}}}
+	result := registry.Validate(context.TODO(), &ValidatorRequest{
+		AdmissionRequest: &v1beta1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Kind: "VerrazzanoModel"},
+			Namespace: model.Namespace,
+			Name:      model.Name,
+			Operation: v1beta1.Delete,
+		},
+		Clientsets: &Clientsets{V8oClient: NewFakeVzClient(model, binding), K8sClient: k8sfake.NewSimpleClientset()},
+		Policy:     &Policy{DefaultRuleMode: RuleModeOff, RuleModes: map[string]RuleMode{ruleModelInUse: RuleModeOff}},
+	})
+	assert.False(t, result.Allowed)
+	assert.Equal(t, ruleModelInUse, result.Problems[0].Rule)
+	assert.Contains(t, result.Message, "model cannot be deleted before binding")
+}
+
+// TestRuleModesSecretInUse tests that the deletion of a secret referenced by a model follows the mode of its rule
+// GIVEN a model referencing a secret and a policy setting the secret-in-use rule to warn
+//  WHEN the secret validator validates the deletion of the secret
+//  THEN the request should be allowed with a warning
+func TestRuleModesSecretInUse(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	registry := &ValidatorRegistry{validators: []Validator{secretValidator{}}}
+	result := registry.Validate(context.TODO(), &ValidatorRequest{
+		AdmissionRequest: &v1beta1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+			Namespace: "default",
+			Name:      "mysql-credentials",
+			Operation: v1beta1.Delete,
+		},
+		Clientsets: &Clientsets{V8oClient: NewFakeVzClient(model), K8sClient: k8sfake.NewSimpleClientset()},
+		Policy:     &Policy{RuleModes: map[string]RuleMode{ruleSecretInUse: RuleModeWarn}},
+	})
+	assert.True(t, result.Allowed)
+	assert.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "secret mysql-credentials is referenced by component(s) mysql")
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	s "strings"
+
+	"go.uber.org/zap"
+	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Timeout of the webhook of the secret validator, deletions are not delayed long when the models can't be listed
+const secretWebhookTimeoutSeconds = 10
+
+// Label with the name of a namespace, set by the API server from Kubernetes 1.21.  Older clusters need the default
+// namespace to be labeled for its secrets to be protected.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// Protects the secrets referenced by models from being deleted.  Models reference secrets of the default
+// namespace, the deletions of secrets of other namespaces are not sent to the webhook.  Failures are ignored so
+// that secrets can still be deleted when the webhook is not available.
+type secretValidator struct{}
+
+func (secretValidator) Registration() ValidatorRegistration {
+	return ValidatorRegistration{Kind: "Secret", Group: "", Version: "v1", Resource: "secrets",
+		Operations: []admissionv1beta1.OperationType{admissionv1beta1.Delete}, FailurePolicy: admissionv1beta1.Ignore,
+		TimeoutSeconds: secretWebhookTimeoutSeconds,
+		NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: namespaceNameLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"default"}}}}}
+}
+
+func (secretValidator) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	if request.Operation != v1beta1.Delete || request.Namespace != "default" {
+		return Allow()
+	}
+	zap.S().Debugf("processing secret name: %s:%s", request.Namespace, request.Name)
+
+	// Like the failure policy of the webhook, allow the deletion when the models can't be checked
+	modelList, err := listModels(ctx, request.Clientsets, "")
+	if err != nil {
+		zap.S().Errorf("failed to list models to check the references to secret %s, allowing the deletion: %v", request.Name, err)
+		return Allow()
+	}
+	var problems []Problem
+	for _, model := range modelList.Items {
+		var components []string
+		for _, reference := range modelSecretReferences(model) {
+			if reference.name == request.Name && !containsString(components, reference.component) {
+				components = append(components, reference.component)
+			}
+		}
+		if len(components) > 0 {
+			problems = append(problems, newProblem("secret %s is referenced by component(s) %s of model %s in namespace %s and can't be deleted",
+				request.Name, s.Join(components, ", "), model.Name, model.Namespace))
+		}
+	}
+	if len(problems) > 0 {
+		zap.S().Errorw(problemsMessage(problems))
+	}
+	return problemsResult(withRule(ruleSecretInUse, problems))
+}
+
+// Check whether a list of strings contains a string
+func containsString(values []string, value string) bool {
+	for _, other := range values {
+		if other == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v8ofake "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/fake"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// TestSecretValidator tests protection of the secrets referenced by models
+// GIVEN a model referencing secrets of the default namespace
+//  WHEN the deletion of secrets is validated
+//  THEN the deletion of the secrets referenced by the model should be denied
+func TestSecretValidator(t *testing.T) {
+	model := ReadModel("testdata/bobs-books-v2-model.yaml")
+	clientsets := &Clientsets{V8oClient: v8ofake.NewSimpleClientset(model).VerrazzanoV1beta1(), K8sClient: k8sfake.NewSimpleClientset()}
+
+	tests := []struct {
+		name            string
+		namespace       string
+		secret          string
+		operation       v1beta1.Operation
+		expectedMessage string
+	}{
+		{
+			name:            "TestDeleteImagePullSecret",
+			namespace:       "default",
+			secret:          "github-packages",
+			operation:       v1beta1.Delete,
+			expectedMessage: "secret github-packages is referenced by component(s) bobbys-helidon-stock-application, bobbys-coherence of model bobs-books-model in namespace default and can't be deleted",
+		}, {
+			name:            "TestDeleteEnvSecret",
+			namespace:       "default",
+			secret:          "mysql-credentials",
+			operation:       v1beta1.Delete,
+			expectedMessage: "secret mysql-credentials is referenced by component(s) mysql of model bobs-books-model in namespace default and can't be deleted",
+		}, {
+			name:      "TestDeleteUnreferencedSecret",
+			namespace: "default",
+			secret:    "unused",
+			operation: v1beta1.Delete,
+		}, {
+			name:      "TestDeleteSecretOfOtherNamespace",
+			namespace: "other",
+			secret:    "github-packages",
+			operation: v1beta1.Delete,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := secretValidator{}.Validate(context.TODO(), &ValidatorRequest{
+				AdmissionRequest: &v1beta1.AdmissionRequest{
+					Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+					Namespace: test.namespace,
+					Name:      test.secret,
+					Operation: test.operation,
+				},
+				Clientsets: clientsets,
+			})
+			assert.Equal(t, test.expectedMessage == "", result.Allowed)
+			assert.Equal(t, test.expectedMessage, result.Message)
+		})
+	}
+}
+
+// TestSecretValidatorListFailure tests deletion of secrets when the models can't be listed
+// GIVEN a client failing to list models
+//  WHEN the deletion of a secret is validated
+//  THEN the deletion should be allowed
+func TestSecretValidatorListFailure(t *testing.T) {
+	v8oClient := v8ofake.NewSimpleClientset()
+	v8oClient.PrependReactor("list", "verrazzanomodels", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	clientsets := &Clientsets{V8oClient: v8oClient.VerrazzanoV1beta1(), K8sClient: k8sfake.NewSimpleClientset()}
+
+	result := secretValidator{}.Validate(context.TODO(), &ValidatorRequest{
+		AdmissionRequest: &v1beta1.AdmissionRequest{Namespace: "default", Name: "ocr", Operation: v1beta1.Delete},
+		Clientsets:       clientsets,
+	})
+	assert.True(t, result.Allowed)
+}
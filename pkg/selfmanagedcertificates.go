@@ -0,0 +1,267 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Keys of the webhook certificate secret.  The serving key pair uses the same keys as the secret created by
+// test/certs/create-cert.sh so that the secret can also be mounted.
+const (
+	secretCertKey   = "cert.pem"
+	secretKeyKey    = "key.pem"
+	secretCACertKey = "ca.pem"
+	secretCAKeyKey  = "ca-key.pem"
+)
+
+// SelfManagedCertificates generates the CA and serving certificate of the webhook, stores them in a secret,
+// injects the CA in the caBundle of the ValidatingWebhookConfiguration and renews them before they expire
+type SelfManagedCertificates struct {
+	K8sClient       kubernetes.Interface
+	SecretNamespace string
+	SecretName      string
+	// Name of the ValidatingWebhookConfiguration whose webhooks get the CA bundle
+	WebhookConfigurationName string
+	// DNS names of the webhook service, the first one is the common name of the serving certificate
+	DNSNames []string
+	// Validity of the CA and serving certificates
+	CAValidity      time.Duration
+	ServingValidity time.Duration
+	// Certificates are renewed when they expire within this duration
+	RenewBefore time.Duration
+	// Reloader serving the certificate
+	Reloader *CertificateReloader
+
+	mutex sync.RWMutex
+	caPEM []byte
+}
+
+// CABundle returns the PEM encoded CA certificate, nil until the certificates have been reconciled
+func (m *SelfManagedCertificates) CABundle() []byte {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.caPEM
+}
+
+// Run reconciles the certificates at the given interval until the stop channel is closed
+func (m *SelfManagedCertificates) Run(interval time.Duration, stopCh <-chan struct{}) {
+	go wait.Until(func() {
+		if err := m.Reconcile(); err != nil {
+			zap.S().Errorf("Failed to reconcile webhook certificates: %v", err)
+		}
+	}, interval, stopCh)
+}
+
+// Reconcile makes sure the secret contains a CA and a serving certificate that don't expire soon, serves the
+// serving certificate and injects the CA in the webhook configuration
+func (m *SelfManagedCertificates) Reconcile() error {
+	secrets := m.K8sClient.CoreV1().Secrets(m.SecretNamespace)
+	secret, err := secrets.Get(context.TODO(), m.SecretName, metav1.GetOptions{})
+	exists := err == nil
+	if k8sErrors.IsNotFound(err) {
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: m.SecretNamespace, Name: m.SecretName}}
+	} else if err != nil {
+		return fmt.Errorf("failed to get secret %s in namespace %s: %v", m.SecretName, m.SecretNamespace, err)
+	}
+
+	now := time.Now()
+	data := make(map[string][]byte)
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+	caCert, caKey, err := parseKeyPair(data[secretCACertKey], data[secretCAKeyKey])
+	if err != nil || !caCert.IsCA || now.Add(m.RenewBefore).After(caCert.NotAfter) {
+		zap.S().Infof("Generating webhook CA certificate in secret %s in namespace %s", m.SecretName, m.SecretNamespace)
+		if data[secretCACertKey], data[secretCAKeyKey], err = m.generateCertificate(nil, nil, now); err != nil {
+			return err
+		}
+		if caCert, caKey, err = parseKeyPair(data[secretCACertKey], data[secretCAKeyKey]); err != nil {
+			return err
+		}
+	}
+	if !m.isServingCertificateValid(data[secretCertKey], data[secretKeyKey], caCert, now) {
+		zap.S().Infof("Generating webhook serving certificate in secret %s in namespace %s", m.SecretName, m.SecretNamespace)
+		if data[secretCertKey], data[secretKeyKey], err = m.generateCertificate(caCert, caKey, now); err != nil {
+			return err
+		}
+	}
+
+	if !secretDataEqual(secret.Data, data) {
+		secret.Data = data
+		if !exists {
+			_, err = secrets.Create(context.TODO(), secret, metav1.CreateOptions{})
+		} else {
+			_, err = secrets.Update(context.TODO(), secret, metav1.UpdateOptions{})
+		}
+		// Another replica may have created or renewed the certificates at the same time, its certificates are used
+		if k8sErrors.IsAlreadyExists(err) || k8sErrors.IsConflict(err) {
+			zap.S().Infof("Secret %s in namespace %s was saved by another replica, loading its certificates", m.SecretName, m.SecretNamespace)
+			if secret, err = secrets.Get(context.TODO(), m.SecretName, metav1.GetOptions{}); err != nil {
+				return fmt.Errorf("failed to get secret %s in namespace %s: %v", m.SecretName, m.SecretNamespace, err)
+			}
+			data = secret.Data
+		} else if err != nil {
+			return fmt.Errorf("failed to save secret %s in namespace %s: %v", m.SecretName, m.SecretNamespace, err)
+		}
+	}
+
+	current, _ := m.Reloader.Certificate()
+	if current == nil || !bytes.Equal(pemBlock(current.Certificate[0]), data[secretCertKey]) {
+		if err := m.Reloader.SetKeyPair(data[secretCertKey], data[secretKeyKey]); err != nil {
+			return fmt.Errorf("failed to load serving certificate: %v", err)
+		}
+	}
+	m.mutex.Lock()
+	m.caPEM = data[secretCACertKey]
+	m.mutex.Unlock()
+	return m.injectCABundle(data[secretCACertKey])
+}
+
+// Check that a serving certificate is signed by the CA, is for the DNS names of the webhook and doesn't expire soon
+func (m *SelfManagedCertificates) isServingCertificateValid(certPEM []byte, keyPEM []byte, caCert *x509.Certificate, now time.Time) bool {
+	cert, _, err := parseKeyPair(certPEM, keyPEM)
+	if err != nil || now.Add(m.RenewBefore).After(cert.NotAfter) {
+		return false
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	for _, name := range m.DNSNames {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: name, Roots: roots, CurrentTime: now}); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Generate a PEM encoded key pair, a CA if the signer is nil or else a serving certificate signed by it
+func (m *SelfManagedCertificates) generateCertificate(signer *x509.Certificate, signerKey crypto.Signer, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		NotBefore:    now.Add(-5 * time.Minute),
+	}
+	if signer == nil {
+		template.Subject = pkix.Name{CommonName: "verrazzano-validation-ca"}
+		template.NotAfter = now.Add(m.CAValidity)
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+		signer, signerKey = template, key
+	} else {
+		template.Subject = pkix.Name{CommonName: m.DNSNames[0]}
+		template.DNSNames = m.DNSNames
+		template.NotAfter = now.Add(m.ServingValidity)
+		template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		// The serving certificate must not outlive its CA
+		if template.NotAfter.After(signer.NotAfter) {
+			template.NotAfter = signer.NotAfter
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal key: %v", err)
+	}
+	return pemBlock(der), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// Inject the CA in the caBundle of the webhooks of the ValidatingWebhookConfiguration.  The configuration gets
+// the CA when it is created by the WebhookRegistrar.
+func (m *SelfManagedCertificates) injectCABundle(caPEM []byte) error {
+	configurations := m.K8sClient.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	configuration, err := configurations.Get(context.TODO(), m.WebhookConfigurationName, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		zap.S().Infof("ValidatingWebhookConfiguration %s doesn't exist yet, the CA is injected when it is created", m.WebhookConfigurationName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ValidatingWebhookConfiguration %s: %v", m.WebhookConfigurationName, err)
+	}
+	changed := false
+	for i := range configuration.Webhooks {
+		if !bytes.Equal(configuration.Webhooks[i].ClientConfig.CABundle, caPEM) {
+			configuration.Webhooks[i].ClientConfig.CABundle = caPEM
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := configurations.Update(context.TODO(), configuration, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update the caBundle of ValidatingWebhookConfiguration %s: %v", m.WebhookConfigurationName, err)
+	}
+	zap.S().Infof("Updated the caBundle of ValidatingWebhookConfiguration %s", m.WebhookConfigurationName)
+	return nil
+}
+
+// Parse a PEM encoded certificate and its private key
+func parseKeyPair(certPEM []byte, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errors.New("missing certificate or key")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if public, ok := cert.PublicKey.(*ecdsa.PublicKey); !ok || public.X.Cmp(key.X) != 0 || public.Y.Cmp(key.Y) != 0 {
+		return nil, nil, errors.New("the key doesn't match the certificate")
+	}
+	return cert, key, nil
+}
+
+// PEM encode a DER certificate
+func pemBlock(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// Compare the data of two secrets
+func secretDataEqual(a map[string][]byte, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || !bytes.Equal(value, other) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// Create self-managed certificates for the webhook service in verrazzano-system
+func newTestSelfManagedCertificates(k8sClient *k8sfake.Clientset, renewBefore time.Duration) *SelfManagedCertificates {
+	return &SelfManagedCertificates{
+		K8sClient:                k8sClient,
+		SecretNamespace:          "verrazzano-system",
+		SecretName:               "verrazzano-validation",
+		WebhookConfigurationName: "verrazzano-validation",
+		DNSNames:                 []string{"verrazzano-validation.verrazzano-system.svc", "verrazzano-validation"},
+		CAValidity:               365 * 24 * time.Hour,
+		ServingValidity:          10 * 24 * time.Hour,
+		RenewBefore:              renewBefore,
+		Reloader:                 &CertificateReloader{},
+	}
+}
+
+// Count the actions of a verb on a resource
+func countActions(k8sClient *k8sfake.Clientset, verb string, resource string) int {
+	count := 0
+	for _, action := range k8sClient.Actions() {
+		if action.GetVerb() == verb && action.GetResource().Resource == resource {
+			count++
+		}
+	}
+	return count
+}
+
+// TestSelfManagedCertificatesReconcile tests generation and renewal of the webhook certificates
+// GIVEN a ValidatingWebhookConfiguration and no certificate secret
+//  WHEN the certificates are reconciled, reconciled again and reconciled when the serving certificate expires soon
+//  THEN the CA and serving certificate should be generated, stored, served and injected, left unchanged and then
+//	 only the serving certificate should be renewed
+func TestSelfManagedCertificatesReconcile(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset(&v1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "verrazzano-validation"},
+		Webhooks:   []v1beta1.ValidatingWebhook{{Name: "verrazzano-validation.oracle.com"}, {Name: "other.oracle.com"}},
+	})
+	certificates := newTestSelfManagedCertificates(k8sClient, 24*time.Hour)
+	assert.Nil(t, certificates.Reconcile())
+
+	secret, err := k8sClient.CoreV1().Secrets("verrazzano-system").Get(context.TODO(), "verrazzano-validation", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, secret.Data, 4)
+	caPEM := secret.Data[secretCACertKey]
+	configuration, err := k8sClient.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(context.TODO(), "verrazzano-validation", metav1.GetOptions{})
+	assert.Nil(t, err)
+	for _, webhook := range configuration.Webhooks {
+		assert.Equal(t, caPEM, webhook.ClientConfig.CABundle)
+	}
+
+	served, err := certificates.Reloader.GetCertificate(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, secret.Data[secretCertKey], pemBlock(served.Certificate[0]))
+	block, _ := pem.Decode(caPEM)
+	ca, err := x509.ParseCertificate(block.Bytes)
+	assert.Nil(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	_, err = served.Leaf.Verify(x509.VerifyOptions{DNSName: "verrazzano-validation.verrazzano-system.svc", Roots: roots})
+	assert.Nil(t, err)
+
+	// Nothing changes when the certificates are valid
+	assert.Nil(t, certificates.Reconcile())
+	assert.Equal(t, 0, countActions(k8sClient, "update", "secrets"))
+	assert.Equal(t, 1, countActions(k8sClient, "update", "validatingwebhookconfigurations"))
+
+	// The serving certificate expires within the renewal period of another replica
+	renewing := newTestSelfManagedCertificates(k8sClient, 20*24*time.Hour)
+	renewing.Reloader = certificates.Reloader
+	assert.Nil(t, renewing.Reconcile())
+	renewed, err := k8sClient.CoreV1().Secrets("verrazzano-system").Get(context.TODO(), "verrazzano-validation", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, caPEM, renewed.Data[secretCACertKey])
+	assert.NotEqual(t, secret.Data[secretCertKey], renewed.Data[secretCertKey])
+	served, _ = certificates.Reloader.Certificate()
+	assert.Equal(t, renewed.Data[secretCertKey], pemBlock(served.Certificate[0]))
+	assert.Equal(t, 1, countActions(k8sClient, "update", "validatingwebhookconfigurations"))
+}
+
+// TestSelfManagedCertificatesWithoutWebhookConfiguration tests reconciling certificates before the webhook is registered
+// GIVEN no ValidatingWebhookConfiguration
+//  WHEN the certificates are reconciled
+//  THEN the certificate should be served and its CA returned as the CA bundle
+func TestSelfManagedCertificatesWithoutWebhookConfiguration(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	certificates := newTestSelfManagedCertificates(k8sClient, 24*time.Hour)
+	assert.Nil(t, certificates.CABundle())
+	assert.Nil(t, certificates.Reconcile())
+	served, _ := certificates.Reloader.Certificate()
+	assert.NotNil(t, served)
+	assert.Equal(t, 1, countActions(k8sClient, "create", "secrets"))
+	secret, err := k8sClient.CoreV1().Secrets("verrazzano-system").Get(context.TODO(), "verrazzano-validation", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, secret.Data[secretCACertKey], certificates.CABundle())
+}
+
+// TestSelfManagedCertificatesCreatedByAnotherReplica tests reconciling certificates while another replica creates them
+// GIVEN a certificate secret created by another replica after it was read
+//  WHEN the certificates are reconciled
+//  THEN the certificates of the other replica should be served and returned as the CA bundle
+func TestSelfManagedCertificatesCreatedByAnotherReplica(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	other := newTestSelfManagedCertificates(k8sClient, 24*time.Hour)
+	assert.Nil(t, other.Reconcile())
+	secret, err := k8sClient.CoreV1().Secrets("verrazzano-system").Get(context.TODO(), "verrazzano-validation", metav1.GetOptions{})
+	assert.Nil(t, err)
+
+	// The first read of the secret happens before the other replica created it
+	read := false
+	k8sClient.PrependReactor("get", "secrets", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if read {
+			return false, nil, nil
+		}
+		read = true
+		return true, nil, k8sErrors.NewNotFound(corev1.Resource("secrets"), "verrazzano-validation")
+	})
+	certificates := newTestSelfManagedCertificates(k8sClient, 24*time.Hour)
+	assert.Nil(t, certificates.Reconcile())
+	assert.Equal(t, 2, countActions(k8sClient, "create", "secrets"))
+	served, _ := certificates.Reloader.Certificate()
+	assert.Equal(t, secret.Data[secretCertKey], pemBlock(served.Certificate[0]))
+	assert.Equal(t, secret.Data[secretCACertKey], certificates.CABundle())
+}
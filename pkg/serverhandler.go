@@ -4,10 +4,12 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
 	v8oclientset "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/typed/verrazzano/v1beta1"
@@ -20,7 +22,19 @@ import (
 
 // ServerHandler listens to admission requests and sends responses
 type ServerHandler struct {
-	VerrazzanoURI string
+	VerrazzanoConfig *VerrazzanoConfig
+	Policy           *Policy
+	// Validators the requests are dispatched to, the default registry if nil
+	Validators *ValidatorRegistry
+	// Overrides of the settings of the webhooks, the deadline of a request is computed from the timeout of the
+	// webhook of its kind
+	WebhookOverrides map[string]WebhookOverride
+	// Maximum time taken by the validation of a request, which also gets at most 80% of the timeout of the webhook
+	// of its kind.  No maximum if zero.
+	ValidationTimeout time.Duration
+	// Allow the requests whose validation times out, they are only allowed when the failure policy of the webhook
+	// of their kind is Ignore otherwise
+	FailOpenOnTimeout bool
 }
 
 // Clientsets contains the clients for needed APIs
@@ -29,10 +43,48 @@ type Clientsets struct {
 	K8sClient kubernetes.Interface
 }
 
-// Serve function receives validation requests for Verrazzano model and bindings
+// HandleValidators registers the handlers of the validation requests, at the path of each kind of the validators
+// and at /validate for all the kinds
+func (sh *ServerHandler) HandleValidators(mux *http.ServeMux) {
+	mux.HandleFunc("/validate", sh.Serve)
+	for _, registration := range sh.validators().Registrations() {
+		mux.HandleFunc(ValidatorPath(registration.Kind), sh.ServeKind(registration.Kind))
+	}
+}
+
+// Serve function receives validation requests for all the kinds of the validators, it serves the webhook
+// configurations of previous versions which send all the kinds to /validate
 func (sh *ServerHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	sh.serve(w, r, "")
+}
+
+// ServeKind returns a handler receiving the validation requests of a kind
+func (sh *ServerHandler) ServeKind(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sh.serve(w, r, kind)
+	}
+}
+
+// Get the validators the requests are dispatched to
+func (sh *ServerHandler) validators() *ValidatorRegistry {
+	if sh.Validators == nil {
+		return DefaultValidators
+	}
+	return sh.Validators
+}
+
+// Dispatch a validation request to the validators of its kind, which must be the expected kind if set
+func (sh *ServerHandler) serve(w http.ResponseWriter, r *http.Request, expectedKind string) {
 	zap.S().Infow("Received validation request")
 
+	// The kind, operation and decision are updated as the request is processed
+	start := time.Now()
+	kind, operation, decision := "unknown", "unknown", decisionError
+	var problems []Problem
+	defer func() {
+		recordAdmission(kind, operation, decision, problems, start)
+	}()
+
 	var body []byte
 	if r.Body != nil {
 		if data, err := ioutil.ReadAll(r.Body); err == nil {
@@ -45,12 +97,6 @@ func (sh *ServerHandler) Serve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.URL.Path != "/validate" {
-		zap.S().Errorf("URL prefix %s is not valid", r.URL.Path)
-		http.Error(w, fmt.Sprintf("URL prefix %s is not valid", r.URL.Path), http.StatusBadRequest)
-		return
-	}
-
 	arRequest := v1beta1.AdmissionReview{}
 	if err := json.Unmarshal(body, &arRequest); err != nil {
 		zap.S().Errorf("error with unmarshal of request body: %v", err)
@@ -58,15 +104,40 @@ func (sh *ServerHandler) Serve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if arRequest.Request == nil {
+		zap.S().Errorw("admission review has no request")
+		http.Error(w, "admission review has no request", http.StatusBadRequest)
+		return
+	}
 	zap.S().Infof("%s operation requested on resource %s", arRequest.Request.Operation, arRequest.Request.Kind.Kind)
 	zap.S().Debugf("REQUEST: %+v", arRequest.Request)
 
+	if expectedKind != "" && arRequest.Request.Kind.Kind != expectedKind {
+		zap.S().Errorf("resource kind %s can't be validated at %s", arRequest.Request.Kind.Kind, r.URL.Path)
+		http.Error(w, fmt.Sprintf("resource kind %s can't be validated at %s", arRequest.Request.Kind.Kind, r.URL.Path), http.StatusBadRequest)
+		return
+	}
+	validators := sh.validators()
+	if !validators.Handles(arRequest.Request.Kind.Kind) {
+		zap.S().Errorf("invalid resource kind %s specified", arRequest.Request.Kind.Kind)
+		http.Error(w, fmt.Sprintf("invalid resource kind %s specified", arRequest.Request.Kind.Kind), http.StatusBadRequest)
+		return
+	}
+	// The labels of the metrics are only set from the request once it is known to be for a registered kind, so
+	// that callers can't create unbounded label values
+	kind = arRequest.Request.Kind.Kind
+	if isAdmissionOperation(arRequest.Request.Operation) {
+		operation = string(arRequest.Request.Operation)
+	}
+
 	var arResponse = v1beta1.AdmissionReview{}
+	var result ValidatorResult
 
 	clientsets, err := createClientsets()
 	if err != nil {
 		message := fmt.Sprintf("error getting clientsets: %v", err)
 		zap.S().Errorw(message)
+		result = Deny(message)
 		arResponse = v1beta1.AdmissionReview{
 			Response: &v1beta1.AdmissionResponse{
 				Allowed: false,
@@ -76,48 +147,16 @@ func (sh *ServerHandler) Serve(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 	} else {
-		switch arRequest.Request.Kind.Kind {
-		case "VerrazzanoModel":
-			if arRequest.Request.Operation != v1beta1.Delete {
-				model := v1beta1v8o.VerrazzanoModel{}
-				if err := json.Unmarshal(arRequest.Request.Object.Raw, &model); err != nil {
-					zap.S().Errorf("error with unmarshal of VerrazzanoModel: %v", err)
-					arResponse = v1beta1.AdmissionReview{
-						Response: &v1beta1.AdmissionResponse{
-							Allowed: false,
-							Result: &metav1.Status{
-								Message: fmt.Sprintf("error with unmarshal of VerrazzanoModel: %v", err),
-							},
-						},
-					}
-					break
-				}
-				zap.S().Infof("processing model name: %s:%s", model.Namespace, model.Name)
-				arResponse = validateModel(model, clientsets)
-			} else {
-				zap.S().Infof("processing model name: %s:%s", arRequest.Request.Namespace, arRequest.Request.Name)
-				arResponse = deleteModel(arRequest, clientsets)
-			}
-		case "VerrazzanoBinding":
-			binding := v1beta1v8o.VerrazzanoBinding{}
-			if err := json.Unmarshal(arRequest.Request.Object.Raw, &binding); err != nil {
-				zap.S().Errorf("error with unmarshal of VerrazzanoBinding: %v", err)
-				arResponse = v1beta1.AdmissionReview{
-					Response: &v1beta1.AdmissionResponse{
-						Allowed: false,
-						Result: &metav1.Status{
-							Message: fmt.Sprintf("error with unmarshal of VerrazzanoBinding: %v", err),
-						},
-					},
-				}
-				break
-			}
-			zap.S().Infof("processing binding name: %s:%s", binding.Namespace, binding.Name)
-			arResponse = validateBinding(arRequest, binding, clientsets, sh.VerrazzanoURI)
-		default:
-			zap.S().Errorf("invalid resource kind %s specified", arRequest.Request.Kind.Kind)
-			http.Error(w, fmt.Sprintf("invalid resource kind %s specified", arRequest.Request.Kind.Kind), http.StatusBadRequest)
-			return
+		registration, _ := validators.Registration(arRequest.Request.Kind.Kind)
+		timeout, failOpen := validationDeadline(registration, sh.WebhookOverrides, sh.ValidationTimeout, sh.FailOpenOnTimeout)
+		result = validateWithDeadline(r.Context(), validators, &ValidatorRequest{
+			AdmissionRequest: arRequest.Request,
+			Clientsets:       clientsets,
+			VerrazzanoURI:    sh.VerrazzanoConfig.URI(),
+			Policy:           sh.Policy,
+		}, timeout, failOpen)
+		if !result.Allowed {
+			arResponse = errorAdmissionReview(result.problems())
 		}
 	}
 
@@ -132,7 +171,8 @@ func (sh *ServerHandler) Serve(w http.ResponseWriter, r *http.Request) {
 	// Copy the request UID to the response UID
 	arResponse.Response.UID = arRequest.Request.UID
 
-	resp, err := json.Marshal(arResponse)
+	// The warnings and audited problems of the rules that are not enforced are added to the response
+	resp, err := json.Marshal(withRuleModeResults(arResponse, result))
 	if err != nil {
 		zap.S().Errorf("error with marshal of response: %v", err)
 		http.Error(w, fmt.Sprintf("error with marshal of response: %v", err), http.StatusInternalServerError)
@@ -142,7 +182,25 @@ func (sh *ServerHandler) Serve(w http.ResponseWriter, r *http.Request) {
 	if _, err := w.Write(resp); err != nil {
 		zap.S().Errorf("error with write of response: %v", err)
 		http.Error(w, fmt.Sprintf("error with write of response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if arResponse.Response.Allowed {
+		decision = decisionAllowed
+	} else {
+		decision = decisionDenied
+		if arResponse.Response.Result != nil {
+			problems = result.problems()
+		}
+	}
+}
+
+// Check whether an operation is one of the operations of admission requests
+func isAdmissionOperation(operation v1beta1.Operation) bool {
+	switch operation {
+	case v1beta1.Create, v1beta1.Update, v1beta1.Delete, v1beta1.Connect:
+		return true
 	}
+	return false
 }
 
 func createClientsets() (*Clientsets, error) {
@@ -150,6 +208,7 @@ func createClientsets() (*Clientsets, error) {
 	cfg, err := clientcmd.BuildConfigFromFlags("", "")
 	if err != nil {
 		zap.S().Errorf("Error building kubeconfig: %v", err)
+		clientsetErrors.Inc()
 		return nil, err
 	}
 
@@ -157,6 +216,7 @@ func createClientsets() (*Clientsets, error) {
 	v8oclient, err := v8oclientset.NewForConfig(cfg)
 	if err != nil {
 		zap.S().Errorf("Error building Verrazzano clientset: %v", err)
+		clientsetErrors.Inc()
 		return nil, err
 	}
 
@@ -164,6 +224,7 @@ func createClientsets() (*Clientsets, error) {
 	k8sclient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		zap.S().Errorf("Error building kubernetes clientset: %v", err)
+		clientsetErrors.Inc()
 		return nil, err
 	}
 
@@ -172,3 +233,35 @@ func createClientsets() (*Clientsets, error) {
 		K8sClient: k8sclient,
 	}, nil
 }
+
+// Get a model
+func getModel(ctx context.Context, clientsets *Clientsets, namespace string, name string) (*v1beta1v8o.VerrazzanoModel, error) {
+	defer observeLookup(lookupGetModel, time.Now())
+	model, err := clientsets.V8oClient.VerrazzanoModels(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordTimedOutLookup(ctx, err, fmt.Sprintf("model %s in namespace %s", name, namespace))
+	return model, err
+}
+
+// List the models of a namespace, or of all namespaces if the namespace is empty
+func listModels(ctx context.Context, clientsets *Clientsets, namespace string) (*v1beta1v8o.VerrazzanoModelList, error) {
+	defer observeLookup(lookupListModels, time.Now())
+	models, err := clientsets.V8oClient.VerrazzanoModels(namespace).List(ctx, metav1.ListOptions{})
+	recordTimedOutLookup(ctx, err, "models in "+namespaceDescription(namespace))
+	return models, err
+}
+
+// List the bindings of a namespace, or of all namespaces if the namespace is empty
+func listBindings(ctx context.Context, clientsets *Clientsets, namespace string) (*v1beta1v8o.VerrazzanoBindingList, error) {
+	defer observeLookup(lookupListBindings, time.Now())
+	bindings, err := clientsets.V8oClient.VerrazzanoBindings(namespace).List(ctx, metav1.ListOptions{})
+	recordTimedOutLookup(ctx, err, "bindings in "+namespaceDescription(namespace))
+	return bindings, err
+}
+
+// Describe the namespace of a list, all namespaces if empty
+func namespaceDescription(namespace string) string {
+	if namespace == "" {
+		return "all namespaces"
+	}
+	return "namespace " + namespace
+}
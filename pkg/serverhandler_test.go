@@ -0,0 +1,93 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestHandleValidators tests routing of the validation requests
+// GIVEN the handlers of the validators registered in a mux
+//  WHEN requests are sent to the validation paths
+//  THEN each kind should have a path, requests for other kinds should be rejected and recorded without their kind
+func TestHandleValidators(t *testing.T) {
+	mux := http.NewServeMux()
+	sh := &ServerHandler{}
+	sh.HandleValidators(mux)
+
+	tests := []struct {
+		name            string
+		path            string
+		kind            string
+		expectedPattern string
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "TestOtherKindAtModelPath",
+			path:            "/validate/verrazzanomodel",
+			kind:            "VerrazzanoBinding",
+			expectedPattern: "/validate/verrazzanomodel",
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "resource kind VerrazzanoBinding can't be validated at /validate/verrazzanomodel\n",
+		}, {
+			name:            "TestOtherKindAtSecretPath",
+			path:            "/validate/secret",
+			kind:            "ConfigMap",
+			expectedPattern: "/validate/secret",
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "resource kind ConfigMap can't be validated at /validate/secret\n",
+		}, {
+			name:            "TestUnknownKindAtValidatePath",
+			path:            "/validate",
+			kind:            "ConfigMap",
+			expectedPattern: "/validate",
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "invalid resource kind ConfigMap specified\n",
+		}, {
+			name:           "TestUnknownPath",
+			path:           "/validate/configmap",
+			kind:           "ConfigMap",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := json.Marshal(v1beta1.AdmissionReview{Request: &v1beta1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Kind: test.kind},
+				Operation: v1beta1.Create,
+			}})
+			assert.Nil(t, err)
+			request := httptest.NewRequest(http.MethodPost, test.path, bytes.NewReader(body))
+			_, pattern := mux.Handler(request)
+			assert.Equal(t, test.expectedPattern, pattern)
+
+			kindRequests := testutil.ToFloat64(admissionRequests.WithLabelValues(test.kind, "CREATE", decisionError))
+			unknownRequests := testutil.ToFloat64(admissionRequests.WithLabelValues("unknown", "unknown", decisionError))
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, kindRequests, testutil.ToFloat64(admissionRequests.WithLabelValues(test.kind, "CREATE", decisionError)))
+			if test.expectedStatus == http.StatusBadRequest {
+				assert.Equal(t, unknownRequests+1, testutil.ToFloat64(admissionRequests.WithLabelValues("unknown", "unknown", decisionError)))
+			}
+		})
+	}
+
+	for _, kind := range []string{"VerrazzanoModel", "VerrazzanoBinding", "Secret"} {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, ValidatorPath(kind), nil))
+		assert.Equal(t, ValidatorPath(kind), pattern)
+	}
+}
@@ -12,27 +12,28 @@ import (
 	k8sValidations "k8s.io/apimachinery/pkg/util/validation"
 )
 
-const invalidNameFormat = "\n* %s: Invalid value: \"%s\": %s"
-
-// Add invalidNameFormat message to list of error messages.
-func addInvalidNameFormatMessage(name string, field string, errMessages []string) []string {
+// Add a problem to a list of problems for each reason a name is not a valid DNS-1123 subdomain
+func addInvalidNameProblems(name string, field string, problems []Problem) []Problem {
 
 	for _, msg := range k8sValidations.IsDNS1123Subdomain(name) {
-		msgOut := fmt.Sprintf(invalidNameFormat, field, name, msg)
-		zap.S().Errorw(msgOut)
-		errMessages = append(errMessages, msgOut)
+		problem := fieldProblem(field, problemTypeInvalid, fmt.Sprintf("\"%s\": %s", name, msg))
+		zap.S().Errorw(problem.Message)
+		problems = append(problems, problem)
 	}
 
-	return errMessages
+	return problems
 }
 
-// Create an error response
-func errorAdmissionReview(errMessage string) v1beta1.AdmissionReview {
+// Create an error response with the problems of a validation.  The status has a cause for each problem.
+func errorAdmissionReview(problems []Problem) v1beta1.AdmissionReview {
 	return v1beta1.AdmissionReview{
 		Response: &v1beta1.AdmissionResponse{
 			Allowed: false,
 			Result: &metav1.Status{
-				Message: errMessage,
+				Message: problemsMessage(problems),
+				Details: &metav1.StatusDetails{
+					Causes: statusCauses(problems),
+				},
 			},
 		},
 	}
@@ -114,6 +114,19 @@ func newSecret(namespace, name, secret string) *corev1.Secret {
 	}
 }
 
+func newImagePullSecret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		StringData: map[string]string{
+			corev1.DockerConfigJsonKey: "{}",
+		},
+	}
+}
+
 // FakeVzClient implements VerrazzanoV1beta1Interface.
 type FakeVzClient struct {
 	ktesting.Fake
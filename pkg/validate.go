@@ -0,0 +1,110 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ValidateModel runs the validators of the webhook for the creation of a model.  Returns the validation
+// message, or an empty string if the model is valid.
+func ValidateModel(model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets, policy *Policy) string {
+	return problemsMessage(validateModelCreate(model, clientsets, policy))
+}
+
+// Run the validators of the webhook for the creation of a model, returns the problems found
+func validateModelCreate(model v1beta1v8o.VerrazzanoModel, clientsets *Clientsets, policy *Policy) []Problem {
+	if model.Namespace == "" {
+		model.Namespace = "default"
+	}
+	return validateCreate(DefaultValidators, "VerrazzanoModel", model.Namespace, model.Name, model, clientsets, "", policy)
+}
+
+// ValidateBinding runs the validators of the webhook for the creation of a binding.  Returns the validation
+// message, or an empty string if the binding is valid.
+func ValidateBinding(binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets, verrazzanoURI string, policy *Policy) string {
+	return problemsMessage(validateBindingCreate(binding, clientsets, verrazzanoURI, policy))
+}
+
+// Run the validators of the webhook for the creation of a binding, returns the problems found
+func validateBindingCreate(binding v1beta1v8o.VerrazzanoBinding, clientsets *Clientsets, verrazzanoURI string, policy *Policy) []Problem {
+	if binding.Namespace == "" {
+		binding.Namespace = "default"
+	}
+	return validateCreate(DefaultValidators, "VerrazzanoBinding", binding.Namespace, binding.Name, binding, clientsets, verrazzanoURI, policy)
+}
+
+// Run the validators of a registry for the creation of a resource, like the webhook does.  Returns the problems
+// that deny the creation, none if the resource is valid.
+func validateCreate(validators *ValidatorRegistry, kind string, namespace string, name string, object interface{},
+	clientsets *Clientsets, verrazzanoURI string, policy *Policy) []Problem {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return []Problem{newProblem("error with marshal of %s: %v", kind, err)}
+	}
+	result := validators.Validate(context.TODO(), &ValidatorRequest{
+		AdmissionRequest: &v1beta1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "verrazzano.io", Version: "v1beta1", Kind: kind},
+			Namespace: namespace,
+			Name:      name,
+			Operation: v1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+		Clientsets:    clientsets,
+		VerrazzanoURI: verrazzanoURI,
+		Policy:        policy,
+	})
+	if result.Allowed {
+		return nil
+	}
+	return result.problems()
+}
+
+// ValidationResult is the result of the validation of a model or binding read from a manifest file
+type ValidationResult struct {
+	Path      string `json:"path"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Validation message, empty if the resource is valid
+	Message string `json:"message,omitempty"`
+	// Positions of the fields of the resource in the file, used to find the positions of the problems
+	Positions FieldPositions `json:"-"`
+	// Problems found by the validation, the message is made of their messages
+	problems []Problem
+}
+
+// Create the result of the validation of a resource read from a manifest file
+func newValidationResult(target Manifest, kind string, namespace string, name string, problems []Problem) ValidationResult {
+	return ValidationResult{Path: target.Path, Kind: kind, Namespace: namespace, Name: name, Message: problemsMessage(problems),
+		Positions: target.Positions, problems: problems}
+}
+
+// ValidateManifests validates the models and bindings of the target manifests.  Lookups of other resources,
+// like secrets, managed clusters and other models, are resolved from the lookup and target manifests.
+// Models are validated before bindings.
+func ValidateManifests(targets []Manifest, lookups []Manifest, verrazzanoURI string, policy *Policy) []ValidationResult {
+	clientsets := NewManifestClientsets(append(append([]Manifest{}, lookups...), targets...))
+
+	var results []ValidationResult
+	for _, target := range targets {
+		if model, ok := target.Object.(*v1beta1v8o.VerrazzanoModel); ok {
+			results = append(results, newValidationResult(target, "VerrazzanoModel", model.Namespace, model.Name,
+				validateModelCreate(*model, clientsets, policy)))
+		}
+	}
+	for _, target := range targets {
+		if binding, ok := target.Object.(*v1beta1v8o.VerrazzanoBinding); ok {
+			results = append(results, newValidationResult(target, "VerrazzanoBinding", binding.Namespace, binding.Name,
+				validateBindingCreate(*binding, clientsets, verrazzanoURI, policy)))
+		}
+	}
+	return results
+}
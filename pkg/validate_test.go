@@ -0,0 +1,142 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const lookupSecrets = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: ocr
+type: kubernetes.io/dockerconfigjson
+stringData:
+  .dockerconfigjson: "{}"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: github-packages
+type: kubernetes.io/dockerconfigjson
+data:
+  .dockerconfigjson: e30=
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: ignored
+`
+
+const lookupList = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: bobbys-front-end-weblogic-credentials
+    namespace: default
+  stringData:
+    username: weblogic
+    password: hello
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: bobs-bookstore-weblogic-credentials
+  stringData:
+    username: weblogic
+    password: hello
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: mysql-credentials
+  stringData:
+    username: mysql
+    password: hello
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: mysql-initdb-config
+`
+
+// Write the lookup manifests to a temporary directory
+func writeLookupManifests(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "manifests")
+	assert.Nil(t, err)
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "secrets"), 0700))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "secrets", "secrets.yaml"), []byte(lookupSecrets), 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "list.yml"), []byte(lookupList), 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0600))
+	return dir
+}
+
+// TestReadManifests tests reading of manifests from a directory
+// GIVEN a directory with manifest files containing several documents and lists
+//  WHEN ReadManifests is called with the directory
+//  THEN the resources of the kinds used by the validations should be returned, in the given namespace if they have none
+func TestReadManifests(t *testing.T) {
+	dir := writeLookupManifests(t)
+	defer os.RemoveAll(dir)
+
+	manifests, err := ReadManifests(dir)
+	assert.Nil(t, err)
+	assert.Len(t, manifests, 6)
+	secret := manifests[0].Object.(*corev1.Secret)
+	assert.Equal(t, filepath.Join(dir, "list.yml"), manifests[0].Path)
+	assert.Equal(t, "default", secret.Namespace)
+	assert.Equal(t, []byte("weblogic"), secret.Data["username"])
+	assert.Nil(t, secret.StringData)
+
+	_, err = ReadManifests(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+
+	manifests, err = ReadManifestsInNamespace(dir, "bob")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", manifests[0].Object.(*corev1.Secret).Namespace)
+	assert.Equal(t, "bob", manifests[1].Object.(*corev1.Secret).Namespace)
+}
+
+// TestValidateManifests tests validation of models and bindings read from manifest files
+// GIVEN model and binding manifests and manifests for the lookups
+//  WHEN ValidateManifests is called
+//  THEN the models and bindings should be validated with lookups resolved from the manifests and lookup problems
+//	 should have the position of the field referencing the missing object
+func TestValidateManifests(t *testing.T) {
+	dir := writeLookupManifests(t)
+	defer os.RemoveAll(dir)
+	lookups, err := ReadManifests(dir)
+	assert.Nil(t, err)
+	cluster, err := ReadManifests("../test/integ/testdata/local-cluster.yaml")
+	assert.Nil(t, err)
+	var targets []Manifest
+	for _, file := range []string{"bobs-books-v2-binding.yaml", "bobs-books-v2-model.yaml"} {
+		manifests, err := ReadManifests("../test/integ/testdata/" + file)
+		assert.Nil(t, err)
+		targets = append(targets, manifests...)
+	}
+
+	results := ValidateManifests(targets, append(lookups, cluster...), "v8o.example.com", DefaultPolicy())
+	assert.Len(t, results, 2)
+	assert.Equal(t, Position{Line: 6, Column: 3}, results[0].Positions["metadata.name"])
+	results[0].Positions, results[1].Positions = nil, nil
+	assert.Equal(t, ValidationResult{Path: "../test/integ/testdata/bobs-books-v2-model.yaml", Kind: "VerrazzanoModel", Namespace: "default", Name: "bobs-books-model"}, results[0])
+	assert.Equal(t, ValidationResult{Path: "../test/integ/testdata/bobs-books-v2-binding.yaml", Kind: "VerrazzanoBinding", Namespace: "default", Name: "bobs-books-binding"}, results[1])
+
+	results = ValidateManifests(targets, lookups, "v8o.example.com", DefaultPolicy())
+	assert.Equal(t, "", results[0].Message)
+	assert.Contains(t, results[1].Message, "binding references cluster(s) \"local\" that do not exist in namespace default")
+	problems := results[1].Problems()
+	assert.Len(t, problems, 1)
+	assert.Equal(t, "spec.placement[0].name", problems[0].Field)
+	assert.Equal(t, 26, problems[0].Line)
+	assert.Equal(t, 7, problems[0].Column)
+}
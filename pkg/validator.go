@@ -0,0 +1,251 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	s "strings"
+	"sync"
+
+	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Settings of the webhook of a kind when they are not set by its validators
+const (
+	defaultWebhookFailurePolicy  = admissionv1beta1.Fail
+	defaultWebhookTimeoutSeconds = int32(30)
+)
+
+// ValidatorRegistration describes the resources and operations a validator handles, used to dispatch admission
+// requests to the validator and to register the webhook
+type ValidatorRegistration struct {
+	Kind       string
+	Group      string
+	Version    string
+	Resource   string
+	Operations []admissionv1beta1.OperationType
+	// Failure policy of the webhook of the kind, Fail if empty
+	FailurePolicy admissionv1beta1.FailurePolicyType
+	// Timeout of the webhook of the kind, 30 seconds if zero
+	TimeoutSeconds int32
+	// Namespaces whose resources are sent to the webhook of the kind, in addition to the namespace selector of the
+	// webhook configuration.  All namespaces if nil.
+	NamespaceSelector *metav1.LabelSelector
+}
+
+// ValidatorPath returns the path the admission requests of a kind are sent to
+func ValidatorPath(kind string) string {
+	return "/validate/" + s.ToLower(kind)
+}
+
+// ValidatorRequest is an admission request with the clients and configuration used by the validators
+type ValidatorRequest struct {
+	*v1beta1.AdmissionRequest
+	Clientsets    *Clientsets
+	VerrazzanoURI string
+	Policy        *Policy
+}
+
+// ValidatorResult is the decision of a validator
+type ValidatorResult struct {
+	Allowed bool
+	// Reason the request is denied, the messages of the problems separated by "; "
+	Message string
+	// Problems that deny the request.  A denied result without problems is reported as a single problem with its
+	// message.
+	Problems []Problem
+	// Problems of the rules in warn mode, returned to the client as warnings
+	Warnings []string
+	// Problems of the rules in audit mode, added to the audit annotations of the request
+	AuditedProblems []string
+}
+
+// Allow returns the result of a validator that allows the request
+func Allow() ValidatorResult {
+	return ValidatorResult{Allowed: true}
+}
+
+// Deny returns the result of a validator that denies the request
+func Deny(message string) ValidatorResult {
+	return ValidatorResult{Allowed: false, Message: message}
+}
+
+// DenyProblems returns the result of a validator that denies the request with the problems it found
+func DenyProblems(problems ...Problem) ValidatorResult {
+	return ValidatorResult{Allowed: false, Message: problemsMessage(problems), Problems: problems}
+}
+
+// Get the result of the problems found by a validator, the request is allowed when there are none
+func problemsResult(problems []Problem) ValidatorResult {
+	if len(problems) == 0 {
+		return Allow()
+	}
+	return DenyProblems(problems...)
+}
+
+// Get the problems of a denied result, the result of a validator that only has a message has a single problem
+func (r ValidatorResult) problems() []Problem {
+	if len(r.Problems) > 0 || r.Allowed {
+		return r.Problems
+	}
+	return []Problem{{Message: r.Message}}
+}
+
+// Validator validates the admission requests of a kind of resource
+type Validator interface {
+	// Registration returns the kind of resource and the operations validated
+	Registration() ValidatorRegistration
+	// Validate validates an admission request for the kind and one of the operations of the registration
+	Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult
+}
+
+// ValidatorRegistry contains the validators the admission requests are dispatched to.  Several validators can
+// be registered for the same kind, they are run in the order they were registered.
+type ValidatorRegistry struct {
+	mutex      sync.RWMutex
+	validators []Validator
+}
+
+// DefaultValidators is the registry used by the webhook, it contains the model, binding and secret validators
+var DefaultValidators = &ValidatorRegistry{validators: []Validator{modelValidator{}, bindingValidator{}, secretValidator{}}}
+
+// RegisterValidator adds a validator to the default registry, typically from the init function of the package
+// of the validator
+func RegisterValidator(validator Validator) error {
+	return DefaultValidators.Register(validator)
+}
+
+// Register adds a validator to the registry
+func (r *ValidatorRegistry) Register(validator Validator) error {
+	registration := validator.Registration()
+	if registration.Kind == "" || registration.Version == "" || registration.Resource == "" {
+		return errors.New("the registration of a validator must have a kind, a version and a resource")
+	}
+	if len(registration.Operations) == 0 {
+		return fmt.Errorf("the registration of the validator of %s must have operations", registration.Kind)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, registered := range r.validators {
+		other := registered.Registration()
+		if other.Kind == registration.Kind && (other.Group != registration.Group || other.Version != registration.Version || other.Resource != registration.Resource) {
+			return fmt.Errorf("kind %s is already registered for resource %s in group %s version %s", registration.Kind,
+				other.Resource, other.Group, other.Version)
+		}
+	}
+	r.validators = append(r.validators, validator)
+	return nil
+}
+
+// Handles returns whether validators are registered for a kind
+func (r *ValidatorRegistry) Handles(kind string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, validator := range r.validators {
+		if validator.Registration().Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Registration returns the registration of a kind with the operations and settings of all its validators
+func (r *ValidatorRegistry) Registration(kind string) (ValidatorRegistration, bool) {
+	for _, registration := range r.Registrations() {
+		if registration.Kind == kind {
+			return registration, true
+		}
+	}
+	return ValidatorRegistration{}, false
+}
+
+// Validators returns the validators of a kind and operation
+func (r *ValidatorRegistry) Validators(kind string, operation v1beta1.Operation) []Validator {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var validators []Validator
+	for _, validator := range r.validators {
+		registration := validator.Registration()
+		if registration.Kind == kind && handlesOperation(registration, operation) {
+			validators = append(validators, validator)
+		}
+	}
+	return validators
+}
+
+// Registrations returns a registration for each resource with the operations of all its validators, in the
+// order the resources were registered.  The failure policy of a resource is Fail unless all its validators
+// ignore failures, and its timeout is the longest timeout of its validators.
+func (r *ValidatorRegistry) Registrations() []ValidatorRegistration {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var registrations []ValidatorRegistration
+	indexes := make(map[string]int)
+	for _, validator := range r.validators {
+		registration := validator.Registration()
+		if registration.FailurePolicy == "" {
+			registration.FailurePolicy = defaultWebhookFailurePolicy
+		}
+		if registration.TimeoutSeconds == 0 {
+			registration.TimeoutSeconds = defaultWebhookTimeoutSeconds
+		}
+		index, ok := indexes[registration.Kind]
+		if !ok {
+			indexes[registration.Kind] = len(registrations)
+			registration.Operations = append([]admissionv1beta1.OperationType{}, registration.Operations...)
+			registrations = append(registrations, registration)
+			continue
+		}
+		merged := &registrations[index]
+		for _, operation := range registration.Operations {
+			if !containsOperation(merged.Operations, operation) {
+				merged.Operations = append(merged.Operations, operation)
+			}
+		}
+		if registration.FailurePolicy != admissionv1beta1.Ignore {
+			merged.FailurePolicy = admissionv1beta1.Fail
+		}
+		if registration.TimeoutSeconds > merged.TimeoutSeconds {
+			merged.TimeoutSeconds = registration.TimeoutSeconds
+		}
+	}
+	return registrations
+}
+
+// Validate runs the validators of the kind and operation of a request.  The request is denied with the messages
+// of all the validators that deny it, and allowed if no validator handles the operation.  The problems of the
+// rules that are not enforced in the namespace of the request are returned as warnings or audited instead.
+func (r *ValidatorRegistry) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	var problems []Problem
+	for _, validator := range r.Validators(request.Kind.Kind, request.Operation) {
+		if result := validator.Validate(ctx, request); !result.Allowed {
+			problems = append(problems, result.problems()...)
+		}
+	}
+	if len(problems) > 0 {
+		return applyRuleModes(ctx, request, problems)
+	}
+	return Allow()
+}
+
+// Check whether a registration handles an operation
+func handlesOperation(registration ValidatorRegistration, operation v1beta1.Operation) bool {
+	return containsOperation(registration.Operations, admissionv1beta1.OperationType(operation)) ||
+		containsOperation(registration.Operations, admissionv1beta1.OperationAll)
+}
+
+// Check whether a list of operations contains an operation
+func containsOperation(operations []admissionv1beta1.OperationType, operation admissionv1beta1.OperationType) bool {
+	for _, other := range operations {
+		if other == operation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1beta1v8o "github.com/verrazzano/verrazzano-crd-generator/pkg/apis/verrazzano/v1beta1"
+	v8ofake "github.com/verrazzano/verrazzano-crd-generator/pkg/client/clientset/versioned/fake"
+	"k8s.io/api/admission/v1beta1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Validator of the names of a kind used by the tests
+type testNameValidator struct {
+	registration ValidatorRegistration
+	forbidden    string
+}
+
+func (v testNameValidator) Registration() ValidatorRegistration {
+	return v.registration
+}
+
+func (v testNameValidator) Validate(ctx context.Context, request *ValidatorRequest) ValidatorResult {
+	if request.Name == v.forbidden {
+		return Deny("metadata.name: Forbidden: the name " + v.forbidden + " is reserved")
+	}
+	return Allow()
+}
+
+// Create a validator forbidding a model name for the given operations
+func newTestModelNameValidator(forbidden string, operations ...admissionv1beta1.OperationType) testNameValidator {
+	return testNameValidator{
+		registration: ValidatorRegistration{Kind: "VerrazzanoModel", Group: "verrazzano.io", Version: "v1beta1",
+			Resource: "verrazzanomodels", Operations: operations},
+		forbidden: forbidden,
+	}
+}
+
+// TestValidatorRegistryRegister tests registration of validators
+// GIVEN validators with valid and invalid registrations
+//  WHEN they are registered
+//  THEN the validators with incomplete registrations or registering a kind for another resource should be rejected
+func TestValidatorRegistryRegister(t *testing.T) {
+	tests := []struct {
+		name         string
+		registration ValidatorRegistration
+		expectedErr  string
+	}{
+		{
+			name: "TestRegisterValid",
+			registration: ValidatorRegistration{Kind: "VerrazzanoModel", Group: "verrazzano.io", Version: "v1beta1", Resource: "verrazzanomodels",
+				Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create}},
+		}, {
+			name: "TestRegisterCoreGroup",
+			registration: ValidatorRegistration{Kind: "Secret", Version: "v1", Resource: "secrets",
+				Operations: []admissionv1beta1.OperationType{admissionv1beta1.Delete}},
+		}, {
+			name:         "TestRegisterWithoutResource",
+			registration: ValidatorRegistration{Kind: "Secret", Version: "v1", Operations: []admissionv1beta1.OperationType{admissionv1beta1.Delete}},
+			expectedErr:  "the registration of a validator must have a kind, a version and a resource",
+		}, {
+			name:         "TestRegisterWithoutOperations",
+			registration: ValidatorRegistration{Kind: "Secret", Version: "v1", Resource: "secrets"},
+			expectedErr:  "the registration of the validator of Secret must have operations",
+		}, {
+			name: "TestRegisterOtherResource",
+			registration: ValidatorRegistration{Kind: "VerrazzanoModel", Group: "verrazzano.io", Version: "v1", Resource: "verrazzanomodels",
+				Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create}},
+			expectedErr: "kind VerrazzanoModel is already registered for resource verrazzanomodels in group verrazzano.io version v1beta1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := &ValidatorRegistry{validators: []Validator{modelValidator{}}}
+			err := registry.Register(testNameValidator{registration: test.registration})
+			if test.expectedErr == "" {
+				assert.Nil(t, err)
+				assert.True(t, registry.Handles(test.registration.Kind))
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Len(t, registry.validators, 1)
+			}
+		})
+	}
+}
+
+// TestValidatorRegistryRegistrations tests the registrations of the resources of a registry
+// GIVEN the default validators and validators for models and secrets
+//  WHEN the registrations of the registry are computed
+//  THEN there should be a registration per resource with the operations, failure policy and timeout of its validators
+func TestValidatorRegistryRegistrations(t *testing.T) {
+	registry := &ValidatorRegistry{validators: []Validator{modelValidator{}, bindingValidator{}, secretValidator{}}}
+	assert.Nil(t, registry.Register(newTestModelNameValidator("reserved", admissionv1beta1.Create, admissionv1beta1.Connect)))
+	assert.Nil(t, registry.Register(testNameValidator{registration: ValidatorRegistration{Kind: "Secret", Version: "v1", Resource: "secrets",
+		Operations: []admissionv1beta1.OperationType{admissionv1beta1.Update}, FailurePolicy: admissionv1beta1.Ignore, TimeoutSeconds: 20}}))
+
+	registrations := registry.Registrations()
+	assert.Len(t, registrations, 3)
+	assert.Equal(t, "verrazzanomodels", registrations[0].Resource)
+	assert.Equal(t, []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update, admissionv1beta1.Delete,
+		admissionv1beta1.Connect}, registrations[0].Operations)
+	assert.Equal(t, "verrazzanobindings", registrations[1].Resource)
+	assert.Equal(t, []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update}, registrations[1].Operations)
+	assert.Equal(t, admissionv1beta1.Fail, registrations[0].FailurePolicy)
+	assert.Equal(t, int32(30), registrations[0].TimeoutSeconds)
+	assert.Equal(t, "secrets", registrations[2].Resource)
+	assert.Equal(t, []admissionv1beta1.OperationType{admissionv1beta1.Delete, admissionv1beta1.Update}, registrations[2].Operations)
+	assert.Equal(t, admissionv1beta1.Ignore, registrations[2].FailurePolicy)
+	assert.Equal(t, int32(20), registrations[2].TimeoutSeconds)
+
+	// The registrations of the validators are not modified
+	assert.Len(t, modelValidator{}.Registration().Operations, 3)
+}
+
+// TestValidatorRegistryValidate tests dispatching of admission requests to the validators of a registry
+// GIVEN a registry with validators forbidding model names for some operations
+//  WHEN requests for models are validated
+//  THEN the request should be denied with the messages of all the validators of the operation that deny it
+func TestValidatorRegistryValidate(t *testing.T) {
+	registry := &ValidatorRegistry{}
+	assert.Nil(t, registry.Register(newTestModelNameValidator("reserved", admissionv1beta1.Create, admissionv1beta1.Update)))
+	assert.Nil(t, registry.Register(newTestModelNameValidator("reserved", admissionv1beta1.OperationAll)))
+	assert.Nil(t, registry.Register(newTestModelNameValidator("system", admissionv1beta1.Update)))
+
+	tests := []struct {
+		name            string
+		kind            string
+		operation       v1beta1.Operation
+		resourceName    string
+		validators      int
+		expectedMessage string
+	}{
+		{
+			name:            "TestValidateCreateDenied",
+			kind:            "VerrazzanoModel",
+			operation:       v1beta1.Create,
+			resourceName:    "reserved",
+			validators:      2,
+			expectedMessage: "metadata.name: Forbidden: the name reserved is reserved; metadata.name: Forbidden: the name reserved is reserved",
+		}, {
+			name:            "TestValidateDeleteDenied",
+			kind:            "VerrazzanoModel",
+			operation:       v1beta1.Delete,
+			resourceName:    "reserved",
+			validators:      1,
+			expectedMessage: "metadata.name: Forbidden: the name reserved is reserved",
+		}, {
+			name:         "TestValidateCreateAllowed",
+			kind:         "VerrazzanoModel",
+			operation:    v1beta1.Create,
+			resourceName: "system",
+			validators:   2,
+		}, {
+			name:            "TestValidateUpdateDenied",
+			kind:            "VerrazzanoModel",
+			operation:       v1beta1.Update,
+			resourceName:    "system",
+			validators:      3,
+			expectedMessage: "metadata.name: Forbidden: the name system is reserved",
+		}, {
+			name:         "TestValidateOtherKind",
+			kind:         "VerrazzanoBinding",
+			operation:    v1beta1.Create,
+			resourceName: "reserved",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Len(t, registry.Validators(test.kind, test.operation), test.validators)
+			result := registry.Validate(context.TODO(), &ValidatorRequest{AdmissionRequest: &v1beta1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Kind: test.kind},
+				Name:      test.resourceName,
+				Operation: test.operation,
+			}})
+			assert.Equal(t, test.expectedMessage == "", result.Allowed)
+			assert.Equal(t, test.expectedMessage, result.Message)
+		})
+	}
+}
+
+// TestValidateWithRegisteredValidators tests validation of manifests by the validators of a registry
+// GIVEN a registry with the model validator and a validator forbidding a model name
+//  WHEN a model with the forbidden name is validated
+//  THEN the message should contain the problems found by both validators
+func TestValidateWithRegisteredValidators(t *testing.T) {
+	registry := &ValidatorRegistry{validators: []Validator{modelValidator{}}}
+	assert.Nil(t, registry.Register(newTestModelNameValidator("reserved", admissionv1beta1.Create)))
+	clientsets := &Clientsets{V8oClient: v8ofake.NewSimpleClientset().VerrazzanoV1beta1(), K8sClient: k8sfake.NewSimpleClientset()}
+
+	model := v1beta1v8o.VerrazzanoModel{}
+	model.Namespace = "default"
+	model.Name = "reserved"
+	model.Spec.GenericComponents = []v1beta1v8o.VerrazzanoGenericComponent{{Name: "Invalid_Name"}}
+	message := problemsMessage(validateCreate(registry, "VerrazzanoModel", model.Namespace, model.Name, model, clientsets, "", DefaultPolicy()))
+	assert.Contains(t, message, "Invalid_Name")
+	assert.Contains(t, message, "metadata.name: Forbidden: the name reserved is reserved")
+
+	model.Name = "valid"
+	model.Spec.GenericComponents = nil
+	assert.Empty(t, validateCreate(registry, "VerrazzanoModel", model.Namespace, model.Name, model, clientsets, "", DefaultPolicy()))
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Key of the ConfigMap containing the Verrazzano URI
+const verrazzanoURIKey = "verrazzanoUri"
+
+// VerrazzanoConfig provides the Verrazzano URI used to create the hostnames of a binding.  The URI is given on the
+// command line and can be overridden by the verrazzanoUri key of a watched ConfigMap.
+type VerrazzanoConfig struct {
+	flagURI string
+
+	mutex        sync.RWMutex
+	configMapURI string
+	// Reports whether the ConfigMap has been read, nil when the ConfigMap is not watched
+	hasSynced func() bool
+}
+
+// NewVerrazzanoConfig returns a configuration that uses the given URI until a URI is discovered
+func NewVerrazzanoConfig(flagURI string) *VerrazzanoConfig {
+	return &VerrazzanoConfig{flagURI: flagURI}
+}
+
+// URI returns the Verrazzano URI, or an empty string if it is not known
+func (c *VerrazzanoConfig) URI() string {
+	if c == nil {
+		return ""
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.configMapURI != "" {
+		return c.configMapURI
+	}
+	return c.flagURI
+}
+
+// Watch keeps the Verrazzano URI up to date with the Verrazzano configuration ConfigMap until the stop
+// channel is closed
+func (c *VerrazzanoConfig) Watch(k8sClient kubernetes.Interface, namespace string, name string, stopCh <-chan struct{}) {
+	zap.S().Infof("Watching ConfigMap %s in namespace %s for the Verrazzano URI", name, namespace)
+	factory := informers.NewSharedInformerFactoryWithOptions(k8sClient, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.update(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.update(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.update(nil)
+		},
+	})
+	c.mutex.Lock()
+	c.hasSynced = informer.HasSynced
+	c.mutex.Unlock()
+	factory.Start(stopCh)
+}
+
+// Synced returns an error if the ConfigMap is watched and has not been read yet
+func (c *VerrazzanoConfig) Synced() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.hasSynced != nil && !c.hasSynced() {
+		return errors.New("the Verrazzano configuration ConfigMap has not been read yet")
+	}
+	return nil
+}
+
+// Update the URI from the ConfigMap, a nil ConfigMap means the ConfigMap was deleted
+func (c *VerrazzanoConfig) update(obj interface{}) {
+	uri := ""
+	if configMap, ok := obj.(*corev1.ConfigMap); ok {
+		uri = configMap.Data[verrazzanoURIKey]
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if uri == c.configMapURI {
+		return
+	}
+	c.configMapURI = uri
+	if uri != "" {
+		zap.S().Infof("Using Verrazzano URI %s from the Verrazzano configuration", uri)
+	} else if c.flagURI != "" {
+		zap.S().Infof("The Verrazzano configuration has no Verrazzano URI, using %s", c.flagURI)
+	} else {
+		zap.S().Warnw("The Verrazzano configuration has no Verrazzano URI, the hostnames of bindings can't be validated")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+)
+
+// TestVerrazzanoConfigURI tests the Verrazzano URI of the configuration
+// GIVEN a Verrazzano configuration with a URI from the command line
+//  WHEN the Verrazzano configuration ConfigMap is updated
+//  THEN the URI from the ConfigMap should be used when it has one and the command line URI otherwise
+func TestVerrazzanoConfigURI(t *testing.T) {
+	config := NewVerrazzanoConfig("flag.example.com")
+	assert.Equal(t, "flag.example.com", config.URI())
+
+	config.update(&corev1.ConfigMap{Data: map[string]string{verrazzanoURIKey: "v8o.example.com"}})
+	assert.Equal(t, "v8o.example.com", config.URI())
+
+	config.update(nil)
+	assert.Equal(t, "flag.example.com", config.URI())
+
+	var nilConfig *VerrazzanoConfig
+	assert.Equal(t, "", nilConfig.URI())
+}
+
+// TestVerrazzanoConfigWatch tests watching of the Verrazzano configuration ConfigMap
+// GIVEN a Verrazzano configuration ConfigMap
+//  WHEN the ConfigMap is watched, updated and deleted
+//  THEN the Verrazzano URI should follow the changes of the ConfigMap
+func TestVerrazzanoConfigWatch(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-system", Name: "verrazzano-config"},
+		Data:       map[string]string{verrazzanoURIKey: "v8o.example.com"},
+	}
+	k8sClient := fakek8s.NewSimpleClientset(configMap)
+	config := NewVerrazzanoConfig("")
+	assert.Nil(t, config.Synced())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	config.Watch(k8sClient, "verrazzano-system", "verrazzano-config", stopCh)
+	assert.Eventually(t, func() bool { return config.Synced() == nil }, 5*time.Second, 10*time.Millisecond)
+
+	uriIs := func(uri string) func() bool {
+		return func() bool { return config.URI() == uri }
+	}
+	assert.Eventually(t, uriIs("v8o.example.com"), 5*time.Second, 10*time.Millisecond)
+
+	configMap.Data[verrazzanoURIKey] = "new.example.com"
+	_, err := k8sClient.CoreV1().ConfigMaps("verrazzano-system").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+	assert.Eventually(t, uriIs("new.example.com"), 5*time.Second, 10*time.Millisecond)
+
+	err = k8sClient.CoreV1().ConfigMaps("verrazzano-system").Delete(context.TODO(), "verrazzano-config", metav1.DeleteOptions{})
+	assert.Nil(t, err)
+	assert.Eventually(t, uriIs(""), 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	s "strings"
+	"time"
+
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WebhookOverride overrides the failure policy and timeout set by the validators of a kind, unset values are not
+// overridden
+type WebhookOverride struct {
+	FailurePolicy  admissionv1beta1.FailurePolicyType
+	TimeoutSeconds int32
+}
+
+// WebhookRegistrar creates the ValidatingWebhookConfiguration of the webhook from the validators it handles and
+// keeps it in sync.  The configuration has a webhook per kind, with the path, failure policy and timeout of the kind.
+type WebhookRegistrar struct {
+	K8sClient kubernetes.Interface
+	// Name of the ValidatingWebhookConfiguration
+	Name string
+	// Suffix of the names of the webhooks in the configuration, the webhook of a kind is named <kind>.<WebhookName>
+	WebhookName      string
+	ServiceNamespace string
+	ServiceName      string
+	// Namespaces whose resources are validated, all namespaces if nil
+	NamespaceSelector *metav1.LabelSelector
+	// Returns the CA bundle used when the webhook has none yet.  Unless ReplaceCABundle is set, the CA bundle of an
+	// existing webhook is kept so that it can be managed by the self-managed certificates or by other tools.
+	CABundle func() []byte
+	// Replace the CA bundles of the existing webhooks by the one returned by CABundle when it isn't empty, so that
+	// a rotated CA read from a file is injected
+	ReplaceCABundle bool
+	// Validators whose resources are sent to the webhook, the default registry if nil
+	Validators *ValidatorRegistry
+	// Overrides of the settings of the webhooks by lower case kind
+	Overrides map[string]WebhookOverride
+}
+
+// Run reconciles the ValidatingWebhookConfiguration at the given interval until the stop channel is closed
+func (r *WebhookRegistrar) Run(interval time.Duration, stopCh <-chan struct{}) {
+	go wait.Until(func() {
+		if err := r.Reconcile(); err != nil {
+			zap.S().Errorf("Failed to reconcile ValidatingWebhookConfiguration %s: %v", r.Name, err)
+		}
+	}, interval, stopCh)
+}
+
+// Reconcile creates the ValidatingWebhookConfiguration, or updates it if it doesn't match the validators
+func (r *WebhookRegistrar) Reconcile() error {
+	configurations := r.K8sClient.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	existing, err := configurations.Get(context.TODO(), r.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		desired := &admissionv1beta1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: r.Name}}
+		desired.Webhooks = r.webhooks(nil)
+		if _, err := configurations.Create(context.TODO(), desired, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create ValidatingWebhookConfiguration %s: %v", r.Name, err)
+		}
+		zap.S().Infof("Created ValidatingWebhookConfiguration %s", r.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ValidatingWebhookConfiguration %s: %v", r.Name, err)
+	}
+
+	webhooks := r.webhooks(existing.Webhooks)
+	if reflect.DeepEqual(webhooks, existing.Webhooks) {
+		return nil
+	}
+	existing.Webhooks = webhooks
+	if _, err := configurations.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ValidatingWebhookConfiguration %s: %v", r.Name, err)
+	}
+	zap.S().Infof("Updated ValidatingWebhookConfiguration %s", r.Name)
+	return nil
+}
+
+// Get the webhooks of the configuration, keeping the CA bundles of the existing webhooks unless they are replaced
+func (r *WebhookRegistrar) webhooks(existing []admissionv1beta1.ValidatingWebhook) []admissionv1beta1.ValidatingWebhook {
+	var caBundle []byte
+	if r.CABundle != nil {
+		caBundle = r.CABundle()
+	}
+
+	// The CA bundle of a webhook of a new kind, or of the webhook of a previous version, is the one of the other
+	// webhooks
+	caBundles := make(map[string][]byte)
+	var defaultCABundle []byte
+	if !r.ReplaceCABundle || len(caBundle) == 0 {
+		for _, webhook := range existing {
+			caBundles[webhook.Name] = webhook.ClientConfig.CABundle
+			if len(defaultCABundle) == 0 {
+				defaultCABundle = webhook.ClientConfig.CABundle
+			}
+		}
+	}
+	if len(defaultCABundle) == 0 {
+		defaultCABundle = caBundle
+	}
+
+	validators := r.Validators
+	if validators == nil {
+		validators = DefaultValidators
+	}
+	var webhooks []admissionv1beta1.ValidatingWebhook
+	for _, registration := range validators.Registrations() {
+		name := s.ToLower(registration.Kind) + "." + r.WebhookName
+		webhookCABundle := caBundles[name]
+		if len(webhookCABundle) == 0 {
+			webhookCABundle = defaultCABundle
+		}
+		webhooks = append(webhooks, r.webhook(name, registration, webhookCABundle))
+	}
+	return webhooks
+}
+
+// Get the webhook of the resource of a registration.  All the fields defaulted by the API server are set so that
+// the configuration is only updated when it changes.
+func (r *WebhookRegistrar) webhook(name string, registration ValidatorRegistration, caBundle []byte) admissionv1beta1.ValidatingWebhook {
+	scope := admissionv1beta1.AllScopes
+	path := ValidatorPath(registration.Kind)
+	port := int32(443)
+	failurePolicy, timeoutSeconds := webhookSettings(registration, r.Overrides)
+	matchPolicy := admissionv1beta1.Equivalent
+	sideEffects := admissionv1beta1.SideEffectClassNone
+	namespaceSelector := mergeLabelSelectors(r.NamespaceSelector, registration.NamespaceSelector)
+	return admissionv1beta1.ValidatingWebhook{
+		Name: name,
+		ClientConfig: admissionv1beta1.WebhookClientConfig{
+			Service:  &admissionv1beta1.ServiceReference{Namespace: r.ServiceNamespace, Name: r.ServiceName, Path: &path, Port: &port},
+			CABundle: caBundle,
+		},
+		Rules: []admissionv1beta1.RuleWithOperations{{
+			Operations: registration.Operations,
+			Rule: admissionv1beta1.Rule{
+				APIGroups:   []string{registration.Group},
+				APIVersions: []string{registration.Version},
+				Resources:   []string{registration.Resource},
+				Scope:       &scope,
+			},
+		}},
+		FailurePolicy:           &failurePolicy,
+		MatchPolicy:             &matchPolicy,
+		NamespaceSelector:       namespaceSelector,
+		ObjectSelector:          &metav1.LabelSelector{},
+		SideEffects:             &sideEffects,
+		TimeoutSeconds:          &timeoutSeconds,
+		AdmissionReviewVersions: []string{"v1beta1"},
+	}
+}
+
+// Merge label selectors into a selector matching the objects matched by all of them, nil selectors match all objects
+func mergeLabelSelectors(selectors ...*metav1.LabelSelector) *metav1.LabelSelector {
+	merged := &metav1.LabelSelector{}
+	for _, selector := range selectors {
+		if selector == nil {
+			continue
+		}
+		for key, value := range selector.MatchLabels {
+			if existing, ok := merged.MatchLabels[key]; ok && existing != value {
+				// Both values are required, which matches no object like the selectors do together
+				merged.MatchExpressions = append(merged.MatchExpressions,
+					metav1.LabelSelectorRequirement{Key: key, Operator: metav1.LabelSelectorOpIn, Values: []string{value}})
+				continue
+			}
+			if merged.MatchLabels == nil {
+				merged.MatchLabels = make(map[string]string)
+			}
+			merged.MatchLabels[key] = value
+		}
+		merged.MatchExpressions = append(merged.MatchExpressions, selector.MatchExpressions...)
+	}
+	return merged
+}
+
+// ParseWebhookOverrides parses comma separated lists of kind=value pairs of failure policies and timeouts in
+// seconds, like "secret=Ignore" and "secret=5,verrazzanomodel=20".  Kinds are case insensitive.
+func ParseWebhookOverrides(failurePolicies string, timeouts string) (map[string]WebhookOverride, error) {
+	overrides := make(map[string]WebhookOverride)
+	policies, err := parseKindValues(failurePolicies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook failure policies: %v", err)
+	}
+	for kind, value := range policies {
+		policy := admissionv1beta1.FailurePolicyType(value)
+		if policy != admissionv1beta1.Fail && policy != admissionv1beta1.Ignore {
+			return nil, fmt.Errorf("invalid webhook failure policy %s for kind %s, must be %s or %s", value, kind, admissionv1beta1.Fail, admissionv1beta1.Ignore)
+		}
+		override := overrides[kind]
+		override.FailurePolicy = policy
+		overrides[kind] = override
+	}
+	seconds, err := parseKindValues(timeouts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook timeouts: %v", err)
+	}
+	for kind, value := range seconds {
+		timeout, err := strconv.Atoi(value)
+		if err != nil || timeout < 1 || timeout > 30 {
+			return nil, fmt.Errorf("invalid webhook timeout %s for kind %s, must be between 1 and 30 seconds", value, kind)
+		}
+		override := overrides[kind]
+		override.TimeoutSeconds = int32(timeout)
+		overrides[kind] = override
+	}
+	return overrides, nil
+}
+
+// Parse a comma separated list of kind=value pairs, by lower case kind
+func parseKindValues(list string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range s.Split(list, ",") {
+		pair = s.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := s.SplitN(pair, "=", 2)
+		if len(parts) != 2 || s.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("%s is not a kind=value pair", pair)
+		}
+		values[s.ToLower(s.TrimSpace(parts[0]))] = s.TrimSpace(parts[1])
+	}
+	return values, nil
+}
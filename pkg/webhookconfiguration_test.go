@@ -0,0 +1,234 @@
+// Copyright (C) 2020, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Create a registrar for the webhook service in verrazzano-system
+func newTestWebhookRegistrar(k8sClient *k8sfake.Clientset) *WebhookRegistrar {
+	return &WebhookRegistrar{
+		K8sClient:        k8sClient,
+		Name:             "verrazzano-validation",
+		WebhookName:      "verrazzano-validation.oracle.com",
+		ServiceNamespace: "verrazzano-system",
+		ServiceName:      "verrazzano-validation",
+		CABundle:         func() []byte { return []byte("ca") },
+	}
+}
+
+// Get the ValidatingWebhookConfiguration of the webhook
+func getWebhookConfiguration(t *testing.T, k8sClient *k8sfake.Clientset) *admissionv1beta1.ValidatingWebhookConfiguration {
+	configuration, err := k8sClient.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(context.TODO(), "verrazzano-validation", metav1.GetOptions{})
+	assert.Nil(t, err)
+	return configuration
+}
+
+// TestWebhookRegistrarCreate tests creation of the ValidatingWebhookConfiguration
+// GIVEN no ValidatingWebhookConfiguration
+//  WHEN the registrar reconciles it twice
+//  THEN the configuration should be created with a webhook for each kind and not updated afterwards
+func TestWebhookRegistrarCreate(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	registrar := newTestWebhookRegistrar(k8sClient)
+	assert.Nil(t, registrar.Reconcile())
+
+	configuration := getWebhookConfiguration(t, k8sClient)
+	assert.Len(t, configuration.Webhooks, len(DefaultValidators.Registrations()))
+	for _, webhook := range configuration.Webhooks {
+		assert.Equal(t, []byte("ca"), webhook.ClientConfig.CABundle)
+		assert.Equal(t, "verrazzano-system", webhook.ClientConfig.Service.Namespace)
+		assert.Equal(t, "verrazzano-validation", webhook.ClientConfig.Service.Name)
+		assert.Equal(t, admissionv1beta1.SideEffectClassNone, *webhook.SideEffects)
+		assert.Len(t, webhook.Rules, 1)
+	}
+
+	model := configuration.Webhooks[0]
+	assert.Equal(t, "verrazzanomodel.verrazzano-validation.oracle.com", model.Name)
+	assert.Equal(t, "/validate/verrazzanomodel", *model.ClientConfig.Service.Path)
+	assert.Equal(t, admissionv1beta1.Fail, *model.FailurePolicy)
+	assert.Equal(t, int32(30), *model.TimeoutSeconds)
+	assert.Equal(t, []string{"verrazzanomodels"}, model.Rules[0].Resources)
+	assert.Equal(t, []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update, admissionv1beta1.Delete}, model.Rules[0].Operations)
+	assert.Equal(t, &metav1.LabelSelector{}, model.NamespaceSelector)
+
+	binding := configuration.Webhooks[1]
+	assert.Equal(t, "verrazzanobinding.verrazzano-validation.oracle.com", binding.Name)
+	assert.Equal(t, "/validate/verrazzanobinding", *binding.ClientConfig.Service.Path)
+	assert.Equal(t, admissionv1beta1.Fail, *binding.FailurePolicy)
+	assert.Equal(t, []string{"verrazzanobindings"}, binding.Rules[0].Resources)
+	assert.Equal(t, []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update}, binding.Rules[0].Operations)
+	assert.Equal(t, &metav1.LabelSelector{}, binding.NamespaceSelector)
+
+	secret := configuration.Webhooks[2]
+	assert.Equal(t, "secret.verrazzano-validation.oracle.com", secret.Name)
+	assert.Equal(t, "/validate/secret", *secret.ClientConfig.Service.Path)
+	assert.Equal(t, admissionv1beta1.Ignore, *secret.FailurePolicy)
+	assert.Equal(t, int32(10), *secret.TimeoutSeconds)
+	assert.Equal(t, []string{""}, secret.Rules[0].APIGroups)
+	assert.Equal(t, []string{"secrets"}, secret.Rules[0].Resources)
+	assert.Equal(t, []admissionv1beta1.OperationType{admissionv1beta1.Delete}, secret.Rules[0].Operations)
+	assert.Equal(t, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpIn, Values: []string{"default"}}}}, secret.NamespaceSelector)
+
+	assert.Nil(t, registrar.Reconcile())
+	assert.Equal(t, 0, countActions(k8sClient, "update", "validatingwebhookconfigurations"))
+}
+
+// TestWebhookRegistrarUpdate tests update of an outdated ValidatingWebhookConfiguration
+// GIVEN a ValidatingWebhookConfiguration of a previous version with a single webhook with a CA bundle
+//  WHEN the registrar reconciles it with a namespace selector
+//  THEN the webhook should be replaced by a webhook per kind with the namespace selector and the CA bundle
+func TestWebhookRegistrarUpdate(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset(&admissionv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "verrazzano-validation"},
+		Webhooks: []admissionv1beta1.ValidatingWebhook{{
+			Name:         "verrazzano-validation.oracle.com",
+			ClientConfig: admissionv1beta1.WebhookClientConfig{CABundle: []byte("existing-ca")},
+			Rules: []admissionv1beta1.RuleWithOperations{{
+				Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create},
+				Rule:       admissionv1beta1.Rule{APIGroups: []string{"verrazzano.io"}, APIVersions: []string{"v1beta1"}, Resources: []string{"verrazzanomodels"}},
+			}},
+		}},
+	})
+	registrar := newTestWebhookRegistrar(k8sClient)
+	registrar.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"verrazzano.io/validation": "enabled"}}
+	assert.Nil(t, registrar.Reconcile())
+
+	configuration := getWebhookConfiguration(t, k8sClient)
+	assert.Len(t, configuration.Webhooks, len(DefaultValidators.Registrations()))
+	for _, webhook := range configuration.Webhooks {
+		assert.NotEqual(t, "verrazzano-validation.oracle.com", webhook.Name)
+		assert.Equal(t, []byte("existing-ca"), webhook.ClientConfig.CABundle)
+		assert.Equal(t, registrar.NamespaceSelector.MatchLabels, webhook.NamespaceSelector.MatchLabels)
+	}
+	assert.Len(t, configuration.Webhooks[2].NamespaceSelector.MatchExpressions, 1, "the secret webhook should keep its namespace")
+	assert.Equal(t, 1, countActions(k8sClient, "update", "validatingwebhookconfigurations"))
+}
+
+// TestWebhookRegistrarReplaceCABundle tests injection of a rotated CA read from a file
+// GIVEN a ValidatingWebhookConfiguration with the CA bundle of a previous CA
+//  WHEN the registrar replacing the CA bundle reconciles it, before and after the CA is rotated
+//  THEN the webhooks should get the current CA bundle
+func TestWebhookRegistrarReplaceCABundle(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	registrar := newTestWebhookRegistrar(k8sClient)
+	assert.Nil(t, registrar.Reconcile())
+
+	caBundle := []byte("rotated-ca")
+	registrar.CABundle = func() []byte { return caBundle }
+	assert.Nil(t, registrar.Reconcile())
+	for _, webhook := range getWebhookConfiguration(t, k8sClient).Webhooks {
+		assert.Equal(t, []byte("ca"), webhook.ClientConfig.CABundle, "the existing CA bundle should be kept")
+	}
+
+	registrar.ReplaceCABundle = true
+	assert.Nil(t, registrar.Reconcile())
+	for _, webhook := range getWebhookConfiguration(t, k8sClient).Webhooks {
+		assert.Equal(t, []byte("rotated-ca"), webhook.ClientConfig.CABundle)
+	}
+
+	// The existing CA bundle is kept while the CA can't be read
+	caBundle = nil
+	assert.Nil(t, registrar.Reconcile())
+	for _, webhook := range getWebhookConfiguration(t, k8sClient).Webhooks {
+		assert.Equal(t, []byte("rotated-ca"), webhook.ClientConfig.CABundle)
+	}
+	assert.Equal(t, 1, countActions(k8sClient, "update", "validatingwebhookconfigurations"))
+}
+
+// TestWebhookRegistrarOverrides tests overriding the failure policy and timeout of the webhook of a kind
+// GIVEN overrides of the failure policy of secrets and of the timeout of models
+//  WHEN the registrar creates the ValidatingWebhookConfiguration
+//  THEN the overridden settings should be used and the other settings should be the ones of the validators
+func TestWebhookRegistrarOverrides(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	registrar := newTestWebhookRegistrar(k8sClient)
+	overrides, err := ParseWebhookOverrides("Secret=Fail", "verrazzanomodel=20")
+	assert.Nil(t, err)
+	registrar.Overrides = overrides
+	assert.Nil(t, registrar.Reconcile())
+
+	configuration := getWebhookConfiguration(t, k8sClient)
+	assert.Equal(t, admissionv1beta1.Fail, *configuration.Webhooks[0].FailurePolicy)
+	assert.Equal(t, int32(20), *configuration.Webhooks[0].TimeoutSeconds)
+	assert.Equal(t, admissionv1beta1.Fail, *configuration.Webhooks[2].FailurePolicy)
+	assert.Equal(t, int32(10), *configuration.Webhooks[2].TimeoutSeconds)
+}
+
+// TestMergeLabelSelectors tests merging of the namespace selectors of the configuration and of a kind
+// GIVEN label selectors with labels and expressions
+//  WHEN they are merged
+//  THEN the merged selector should require the labels and expressions of all of them
+func TestMergeLabelSelectors(t *testing.T) {
+	enabled := &metav1.LabelSelector{MatchLabels: map[string]string{"verrazzano.io/validation": "enabled"}}
+	defaultNamespace := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpIn, Values: []string{"default"}}}}
+	disabled := &metav1.LabelSelector{MatchLabels: map[string]string{"verrazzano.io/validation": "disabled"}}
+
+	assert.Equal(t, &metav1.LabelSelector{}, mergeLabelSelectors(nil, nil))
+	assert.Equal(t, enabled, mergeLabelSelectors(enabled, nil))
+	assert.Equal(t, &metav1.LabelSelector{MatchLabels: enabled.MatchLabels, MatchExpressions: defaultNamespace.MatchExpressions},
+		mergeLabelSelectors(enabled, defaultNamespace))
+	assert.Equal(t, &metav1.LabelSelector{MatchLabels: enabled.MatchLabels, MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "verrazzano.io/validation", Operator: metav1.LabelSelectorOpIn, Values: []string{"disabled"}}}},
+		mergeLabelSelectors(enabled, disabled))
+}
+
+// TestParseWebhookOverrides tests parsing of the overrides of the settings of the webhooks
+// GIVEN lists of failure policies and timeouts by kind
+//  WHEN they are parsed
+//  THEN the overrides should be returned by lower case kind, or an error if a value is invalid
+func TestParseWebhookOverrides(t *testing.T) {
+	tests := []struct {
+		name              string
+		failurePolicies   string
+		timeouts          string
+		expectedOverrides map[string]WebhookOverride
+		expectedErr       string
+	}{
+		{
+			name:              "TestParseEmpty",
+			expectedOverrides: map[string]WebhookOverride{},
+		}, {
+			name:            "TestParseValid",
+			failurePolicies: "Secret=Ignore, verrazzanobinding=Fail",
+			timeouts:        "secret=5",
+			expectedOverrides: map[string]WebhookOverride{
+				"secret":            {FailurePolicy: admissionv1beta1.Ignore, TimeoutSeconds: 5},
+				"verrazzanobinding": {FailurePolicy: admissionv1beta1.Fail},
+			},
+		}, {
+			name:            "TestParseInvalidPair",
+			failurePolicies: "secret",
+			expectedErr:     "invalid webhook failure policies: secret is not a kind=value pair",
+		}, {
+			name:            "TestParseInvalidFailurePolicy",
+			failurePolicies: "secret=Retry",
+			expectedErr:     "invalid webhook failure policy Retry for kind secret, must be Fail or Ignore",
+		}, {
+			name:        "TestParseInvalidTimeout",
+			timeouts:    "secret=60",
+			expectedErr: "invalid webhook timeout 60 for kind secret, must be between 1 and 30 seconds",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			overrides, err := ParseWebhookOverrides(test.failurePolicies, test.timeouts)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedOverrides, overrides)
+		})
+	}
+}
@@ -6,14 +6,10 @@
 BASE_DIR=$(cd $(dirname "$0"); cd ..; pwd -P)
 DOCKER_IMAGE_NAME=$1
 DOCKER_IMAGE_TAG=$2
-CERTS=${BASE_DIR}/build/admission-controller-cert
+VERRAZZANO_URI=${3:-local.v8o.example.com}
 DEPLOY=${BASE_DIR}/build/deploy
 
 mkdir -p "${DEPLOY}"
 
-cat "${BASE_DIR}"/deployment/deployment.yaml | sed -e "s|IMAGE_NAME:IMAGE_TAG|${DOCKER_IMAGE_NAME}:${DOCKER_IMAGE_TAG}|g" > "${DEPLOY}"/deployment.yaml
-
-export CA_BUNDLE="`base64 -i "${CERTS}"/verrazzano-crt.pem | tr -d '\n'`"
-
-sed -i -e "s|CA_BUNDLE|${CA_BUNDLE}|g" "${DEPLOY}"/deployment.yaml
+cat "${BASE_DIR}"/deployment/deployment.yaml | sed -e "s|IMAGE_NAME:IMAGE_TAG|${DOCKER_IMAGE_NAME}:${DOCKER_IMAGE_TAG}|g" -e "s|VERRAZZANO_URI|${VERRAZZANO_URI}|g" > "${DEPLOY}"/deployment.yaml
 
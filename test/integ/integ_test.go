@@ -277,7 +277,7 @@ var _ = Describe("Apply binding", func() {
 		_, stderr := runCommand("kubectl apply -f testdata/min-model.yaml")
 		Expect(stderr).To(Equal(""))
 		_, stderr = runCommand("kubectl apply -f testdata/very-long-name-binding.yaml")
-		Expect(stderr).To(ContainSubstring("the VMI domain name is greater than 64 characters: *.vmi.very-long-name-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa-binding"))
+		Expect(stderr).To(ContainSubstring("the VMI elasticsearch hostname is greater than 64 characters: elasticsearch.vmi.very-long-name-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa-binding"))
 		_, stderr = runCommand("kubectl delete -f testdata/min-model.yaml")
 		Expect(stderr).To(Equal(""))
 	})
@@ -415,10 +415,21 @@ var _ = Describe("Apply model with GenericComponents", func() {
 		deleteSecret("ocr")
 		deleteSecret("init-credentials")
 	})
+	It("with missing volume config map", func() {
+		createSecret("ocr")
+		createSecret("init-credentials")
+		createSecret("mysql-credentials")
+		_, stderr := runCommand("kubectl apply -f testdata/generic-components-model.yaml")
+		Expect(stderr).To(ContainSubstring("model references genericComponents.Deployment.Volumes.ConfigMap \"mysql-initdb-config\""))
+		deleteSecret("mysql-credentials")
+		deleteSecret("init-credentials")
+		deleteSecret("ocr")
+	})
 	It("with all secrets", func() {
 		createSecret("ocr")
 		createSecret("init-credentials")
 		createSecret("mysql-credentials")
+		createConfigMap("mysql-initdb-config")
 		_, stderr := runCommand("kubectl apply -f testdata/generic-components-model.yaml")
 		Expect(stderr).To(Equal(""))
 		_, stderr = runCommand("kubectl apply -f testdata/generic-components-binding.yaml")
@@ -427,6 +438,7 @@ var _ = Describe("Apply model with GenericComponents", func() {
 		Expect(stderr).To(Equal(""))
 		_, stderr = runCommand("kubectl delete -f testdata/generic-components-model.yaml")
 		Expect(stderr).To(Equal(""))
+		deleteConfigMap("mysql-initdb-config")
 		deleteSecret("mysql-credentials")
 		deleteSecret("init-credentials")
 		deleteSecret("ocr")
@@ -435,12 +447,14 @@ var _ = Describe("Apply model with GenericComponents", func() {
 		createSecret("ocr")
 		createSecret("init-credentials")
 		createSecret("mysql-credentials")
+		createConfigMap("mysql-initdb-config")
 		_, stderr := runCommand("kubectl apply -f testdata/generic-components-model.yaml")
 		Expect(stderr).To(Equal(""))
 		_, stderr = runCommand("kubectl apply -f testdata/generic-components-binding-invalid.yaml")
 		Expect(stderr).To(ContainSubstring("Multiple occurrence of component across placement namespaces. Invalid Component: [mysql]"))
 		_, stderr = runCommand("kubectl delete -f testdata/generic-components-model.yaml")
 		Expect(stderr).To(Equal(""))
+		deleteConfigMap("mysql-initdb-config")
 		deleteSecret("mysql-credentials")
 		deleteSecret("init-credentials")
 		deleteSecret("ocr")
@@ -493,6 +507,21 @@ var _ = Describe("Apply binding", func() {
 
 })
 
+var _ = Describe("Delete secret", func() {
+	It("referenced by a model", func() {
+		_, stderr := runCommand("kubectl create secret docker-registry ocr --docker-username=user-id --docker-password=" + testPwd + " --docker-server=container-registry.oracle.com")
+		Expect(stderr).To(Equal(""))
+		_, stderr = runCommand("kubectl apply -f testdata/min-model-with-secret.yaml")
+		Expect(stderr).To(Equal(""))
+		_, stderr = runCommand("kubectl delete secret ocr")
+		Expect(stderr).To(ContainSubstring("secret ocr is referenced by component(s) min-helidon-application of model min-model-with-secret in namespace default and can't be deleted"))
+		_, stderr = runCommand("kubectl delete -f testdata/min-model-with-secret.yaml")
+		Expect(stderr).To(Equal(""))
+		_, stderr = runCommand("kubectl delete secret ocr")
+		Expect(stderr).To(Equal(""))
+	})
+})
+
 func createSecret(name string) string {
 	cmd := fmt.Sprintf("kubectl create secret generic %s --from-literal=username=%s --from-literal=password=%s", name, name, name)
 	_, stderr := runCommand(cmd)
@@ -504,6 +533,18 @@ func deleteSecret(name string) string {
 	return stderr
 }
 
+func createConfigMap(name string) string {
+	cmd := fmt.Sprintf("kubectl create configmap %s --from-literal=name=%s", name, name)
+	_, stderr := runCommand(cmd)
+	return stderr
+}
+
+func deleteConfigMap(name string) string {
+	cmd := fmt.Sprintf("kubectl delete configmap %s", name)
+	_, stderr := runCommand(cmd)
+	return stderr
+}
+
 // ---------------------------   helper functions ------------------------------------
 
 func getKubeconfig() string {